@@ -1,11 +1,18 @@
 package main
 
 import (
+	"log"
+	"os"
 	"time"
 
 	"github.com/moheb2000/fufu/internal/audio"
 	"github.com/moheb2000/fufu/internal/config"
 	"github.com/moheb2000/fufu/internal/gui"
+	"github.com/moheb2000/fufu/internal/locale"
+	"github.com/moheb2000/fufu/internal/save"
+	"github.com/moheb2000/fufu/internal/script"
+	"github.com/moheb2000/fufu/internal/tween"
+	"github.com/moheb2000/fufu/internal/watch"
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
@@ -21,18 +28,47 @@ type Application struct {
 	am         *gui.AnimationManager
 	aum        *audio.AudioManager
 	lua        *Lua
+	loader     *script.Loader
 	state      int
 	result     *int
 	widgets    map[string]gui.Widget
 	dialogs    *gui.List
 	background *Background
+	sprites    []*Sprite
 	splash     *Splash
+	story      *Story
+	saveDir    string
+	seen       *save.Seen
+	settings   *save.Settings
+	locale     *locale.Table
+	skip       bool
+	auto       bool
+	autoTimer  time.Duration
+	// typing are the texts of the current line that the typewriter is showing
+	typing []*gui.Text
+	// errorScreen is the overlay that shows script errors and errorText is the error that can be copied from it
+	errorScreen     gui.Widget
+	errorText       string
+	reloadRequested bool
+	watcher         *watch.Watcher
+	// sceneTexture is the background and sprites drawn together, so transitions can draw the scene as one image
+	sceneTexture *sdl.Texture
+	transition   *Transition
+	// waiting is the timer of wait. The script is resumed when it's finished
+	waiting *tween.Handle
+	// dialogsX and dialogsY move the dialogs from their place in the dialog panel. Scripts can change them with tween
+	dialogsX float64
+	dialogsY float64
+	camera   *Camera
+	// particles are the emitters of weather and particles. pixel is the texture of particles without a texture
+	particles []*Particles
+	pixel     *sdl.Texture
 }
 
 type Lua struct {
-	l  *lua.LState
-	co *lua.LState
-	fn *lua.LFunction
+	l        *lua.LState
+	runner   *script.Runner
+	builtins map[string]bool
 }
 
 const (
@@ -41,6 +77,9 @@ const (
 	SPLASH_STATE
 	MENU_STATE
 	OPTIONS_STATE
+	ERROR_STATE
+	INPUT_STATE
+	WAIT_STATE
 )
 
 // RunApp is responsible for initialization of SDL, running the main loop and cleanup memory
@@ -55,16 +94,62 @@ func (app *Application) RunApp() error {
 	app.result = &result
 	app.am = gui.NewAnimationManager()
 	app.aum = audio.NewAudioManager(app.cfg.FPS)
+	app.story = &Story{}
+	app.camera = newCamera()
+	app.auto = app.cfg.AutoMode.Enabled
+
+	// Game root is the directory of config.json and all script files are loaded relative to it
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	app.loader = script.NewLoader(root)
+
+	// Saves are stored in a directory specific to this game
+	app.saveDir, err = save.Dir(app.cfg.Title)
+	if err != nil {
+		return err
+	}
+
+	// Read lines are shared between all playthroughs, so skip mode can find them after restarting the game
+	app.seen, err = save.LoadSeen(app.saveDir)
+	if err != nil {
+		return err
+	}
+
+	app.settings, err = save.LoadSettings(app.saveDir, save.Settings{
+		SkipMode:  app.cfg.SkipMode,
+		Language:  app.cfg.Language,
+		TextSpeed: app.cfg.TextSpeed,
+	})
+	if err != nil {
+		return err
+	}
+
+	app.loadLocale()
 
 	// Initialize SDL and create the main window
-	err := app.initWindow()
+	err = app.initWindow()
 	if err != nil {
 		return err
 	}
 
-	// Create a new font manager and add a default font
+	// Create a new font manager and add a default font. The language may have its own default font
 	app.fm = gui.NewFontManager()
-	app.fm.LoadFont("default", app.cfg.DefaultFont, 16)
+	if app.locale != nil && app.locale.Font != "" {
+		app.fm.SetLocaleFont(app.settings.Language, app.locale.Font)
+	}
+	if _, err := app.fm.LoadDefaultFont(app.settings.Language, app.cfg.DefaultFont, 16); err != nil {
+		if app.locale == nil || app.locale.Font == "" {
+			return err
+		}
+
+		// The default font of the game is used if the font of the language can't be loaded
+		log.Println("[ERROR] Failed to load the font of the language:", err)
+		if _, err := app.fm.LoadFont("default", app.cfg.DefaultFont, 16); err != nil {
+			return err
+		}
+	}
 
 	// Initialize the main renderer
 	err = app.initRenderer()
@@ -81,7 +166,13 @@ func (app *Application) RunApp() error {
 	// Free memory at the end of the RunAPpp function
 	defer app.cleanup()
 
+	// Script errors are shown in the error overlay, so they can be fixed and reloaded without restarting the engine
 	err = app.initScript()
+	if err != nil {
+		app.showError(err)
+	}
+
+	err = app.initDevMode()
 	if err != nil {
 		return err
 	}
@@ -92,9 +183,28 @@ func (app *Application) RunApp() error {
 
 // cleanup free the memory at the end of the engine
 func (app *Application) cleanup() {
+	if app.watcher != nil {
+		app.watcher.Close()
+	}
+
+	if err := app.seen.Flush(); err != nil {
+		log.Println("[ERROR] Failed to write read lines:", err)
+	}
+
 	if app.background != nil {
 		app.background.Destroy()
 	}
+	app.clearSprites()
+	app.clearParticles()
+	app.finishTransition()
+
+	if app.sceneTexture != nil {
+		app.sceneTexture.Destroy()
+	}
+
+	if app.pixel != nil {
+		app.pixel.Destroy()
+	}
 
 	if app.splash != nil {
 		app.splash.Destroy()
@@ -104,6 +214,8 @@ func (app *Application) cleanup() {
 		widget.Destroy()
 	}
 
+	app.hideError()
+
 	if app.renderer != nil {
 		app.renderer.Destroy()
 	}
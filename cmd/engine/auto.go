@@ -0,0 +1,29 @@
+package main
+
+import (
+	"time"
+)
+
+// updateAuto resumes the script after narrate and say when auto mode is active. The delay starts after the text animations and the voice of the line are finished
+func (app *Application) updateAuto() {
+	if !app.auto || app.state != NOVEL_STATE || (app.story.yield != "narrate" && app.story.yield != "say") {
+		app.autoTimer = 0
+		return
+	}
+
+	if app.am.Len() > 0 || app.aum.IsVoicePlaying() {
+		return
+	}
+
+	app.autoTimer += app.dt
+	if app.autoTimer >= app.autoDelay() {
+		app.resume(0)
+	}
+}
+
+// autoDelay returns how long auto mode waits on the current line. Longer lines need more time to read
+func (app *Application) autoDelay() time.Duration {
+	delay := app.cfg.AutoMode.Delay + app.cfg.AutoMode.CharDelay*app.story.lineLength
+
+	return time.Duration(delay) * time.Millisecond
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Camera moves, zooms and shakes the scene. It changes where the background and sprites are drawn in the scene texture, so the dialog panel doesn't move with it
+type Camera struct {
+	// x and y are the pixels that the camera is moved from its place
+	x    float64
+	y    float64
+	zoom float64
+	// focusX and focusY are the point of the scene from 0 to 1 that stays in its place while zooming
+	focusX float64
+	focusY float64
+	// shake is the intensity of the shake in pixels. The scene is moved by a random offset up to it in every frame
+	shake  float64
+	shakeX float64
+	shakeY float64
+}
+
+func newCamera() *Camera {
+	return &Camera{
+		zoom:   1,
+		focusX: 0.5,
+		focusY: 0.5,
+	}
+}
+
+// update chooses the random offset of the shake for the next frame
+func (c *Camera) update() {
+	if c.shake <= 0 {
+		c.shakeX, c.shakeY = 0, 0
+		return
+	}
+
+	c.shakeX = (rand.Float64()*2 - 1) * c.shake
+	c.shakeY = (rand.Float64()*2 - 1) * c.shake
+}
+
+// apply returns where a rectangle of the scene is drawn in the scene area with the camera
+func (c *Camera) apply(x, y, w, h float64, area *sdl.Rect) *sdl.Rect {
+	fx := float64(area.X) + c.focusX*float64(area.W)
+	fy := float64(area.Y) + c.focusY*float64(area.H)
+
+	x = (x-fx)*c.zoom + fx - c.x + c.shakeX
+	y = (y-fy)*c.zoom + fy - c.y + c.shakeY
+
+	return &sdl.Rect{
+		X: int32(math.Round(x)),
+		Y: int32(math.Round(y)),
+		W: int32(math.Round(w * c.zoom)),
+		H: int32(math.Round(h * c.zoom)),
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/moheb2000/fufu/internal/color"
+	"github.com/moheb2000/fufu/internal/config"
+	"github.com/moheb2000/fufu/internal/headless"
+)
+
+// runCheck runs the game script without a window in every path of choices and prints the problems it finds. The returned value is the exit code of the engine
+func runCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	depth := flags.Int("depth", 8, "number of choices explored in every path of the story. The first option is chosen after them")
+	maxSteps := flags.Int("max-steps", headless.MAX_STEPS, "number of lines in a path before it's reported as a loop")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open the config file:", err)
+		return 1
+	}
+
+	params, cleanup, err := newHeadlessParams(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer cleanup()
+	params.MaxSteps = *maxSteps
+
+	// Every path of choices runs the story from the beginning, so the same issue is found many times
+	var issues []string
+	seen := make(map[string]bool)
+	report := func(issue string) {
+		if !seen[issue] {
+			seen[issue] = true
+			issues = append(issues, issue)
+		}
+	}
+
+	for _, issue := range checkConfig(cfg, params.Root) {
+		report(issue.String())
+	}
+
+	runs := 0
+	headless.Explore(params, *depth, func(result *headless.Result, err error) {
+		runs++
+
+		for _, issue := range result.Issues {
+			report(issue.String())
+		}
+
+		if err != nil {
+			report(err.Error())
+		}
+	})
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	fmt.Fprintf(os.Stderr, "Checked %d paths of the story and found %d issues\n", runs, len(issues))
+
+	if len(issues) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// newHeadlessParams returns the params to run the game in the current directory without a window. cleanup removes the temporary directory of fufu.fs
+func newHeadlessParams(cfg *config.Config) (headless.Params, func(), error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return headless.Params{}, nil, err
+	}
+
+	// Scripts may write files with fufu.fs, so they write to a temporary directory instead of the user data directory
+	saveDir, err := os.MkdirTemp("", "fufu-headless")
+	if err != nil {
+		return headless.Params{}, nil, err
+	}
+
+	params := headless.Params{
+		Root:          root,
+		Script:        cfg.Script,
+		SaveDir:       saveDir,
+		Sandbox:       cfg.Sandbox,
+		EngineVersion: engineVersion,
+		GameVersion:   cfg.GameVersion,
+	}
+
+	return params, func() { os.RemoveAll(saveDir) }, nil
+}
+
+// checkConfig checks the colors and files in config.json
+func checkConfig(cfg *config.Config, root string) []headless.Issue {
+	var issues []headless.Issue
+
+	// Values are checked in the same order every time, so the output of CI doesn't change between runs
+	colors := [][2]string{
+		{"defaultTextColor", cfg.DefaultTextColor},
+		{"dialogPanel.color", cfg.DialogPanel.Color},
+		{"mainMenu.color", cfg.MainMenu.Color},
+		{"mainMenu.colorHover", cfg.MainMenu.ColorHover},
+		{"mainMenu.backgroundColor", cfg.MainMenu.BackgroundColor},
+		{"mainMenu.backgroundColorHover", cfg.MainMenu.BackgroundColorHover},
+	}
+	for _, c := range colors {
+		if _, err := color.ParseHex(c[1]); err != nil {
+			issues = append(issues, headless.Issue{File: "config.json", Message: fmt.Sprintf("%s: %v", c[0], err)})
+		}
+	}
+
+	files := [][2]string{
+		{"defaultFont", cfg.DefaultFont},
+		{"mainMenu.background", cfg.MainMenu.Background},
+	}
+	for _, f := range files {
+		if f[1] == "" {
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(root, f[1])); errors.Is(err, os.ErrNotExist) {
+			issues = append(issues, headless.Issue{File: "config.json", Message: fmt.Sprintf("%s: file %q does not exist", f[0], f[1])})
+		}
+	}
+
+	return issues
+}
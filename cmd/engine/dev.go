@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moheb2000/fufu/internal/locale"
+	"github.com/moheb2000/fufu/internal/watch"
+)
+
+// initDevMode starts watching the game directory for changed files when dev mode is enabled in config
+func (app *Application) initDevMode() error {
+	if !app.cfg.DevMode {
+		return nil
+	}
+
+	watcher, err := watch.New(app.loader.Root(), time.Second/2)
+	if err != nil {
+		return err
+	}
+	watcher.Start()
+	app.watcher = watcher
+
+	log.Println("[DEV] Dev mode is enabled. Changed files of the game will be reloaded")
+
+	return nil
+}
+
+// updateDevMode reloads the game if any file is changed
+func (app *Application) updateDevMode() {
+	if app.watcher == nil {
+		return
+	}
+
+	select {
+	case changes := <-app.watcher.Changes:
+		app.hotReload(changes)
+	default:
+	}
+}
+
+// hotReload reloads the script and the changed assets and then replays the recorded steps, so the story continues from the same line
+func (app *Application) hotReload(changes []string) {
+	log.Println("[DEV] Reloading changed files:", strings.Join(changes, ", "))
+
+	app.loader.Clear()
+
+	// New translations are shown after replaying. A new default font of the language needs a restart
+	if app.settings.Language != "" && slices.Contains(changes, path.Join(locale.DIR, app.settings.Language+".json")) {
+		app.loadLocale()
+	}
+
+	// Before starting the story, only the script needs to be loaded again. Main menu widgets still use the fonts, so they can't be reloaded here
+	if app.state == BOOT_STATE || app.state == MENU_STATE {
+		app.lua.l.Close()
+		if err := app.initScript(); err != nil {
+			app.showError(err)
+		}
+
+		return
+	}
+
+	// Text widgets must be destroyed before their fonts are opened again
+	app.dialogs.Clear()
+	app.hideError()
+
+	for _, file := range changes {
+		switch strings.ToLower(path.Ext(file)) {
+		case ".ttf", ".otf":
+			if err := app.fm.Reload(); err != nil {
+				log.Println("[ERROR] Failed to reload fonts:", err)
+			}
+		case ".mp3", ".wav", ".ogg", ".flac":
+			app.aum.ClearCache()
+		}
+	}
+
+	// Backgrounds and splash screens are created again from their files while replaying
+	app.replay(app.story.steps, app.story.inputs)
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"log"
 	"time"
 
 	"github.com/moheb2000/fufu/internal/gui"
+	"github.com/moheb2000/fufu/internal/save"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -88,11 +90,34 @@ func (app *Application) initDraw() error {
 		}
 
 		app.state = NOVEL_STATE
-		app.lua.l.Resume(app.lua.co, app.lua.fn)
+		app.resume(0)
 		app.widgets["menu"].Destroy()
 		delete(app.widgets, "menu")
 	})
 
+	loadValue, _ := gui.NewText(app.renderer, &gui.TextParams{
+		Value: "Load",
+		Color: bc,
+		Font:  app.fm.GetFont("default", 16),
+	})
+
+	loadButton, _ := gui.NewButton(app.renderer, &gui.ButtonParams{
+		Value:                loadValue,
+		Color:                bc,
+		ColorHover:           bch,
+		BackgroundColor:      bbc,
+		BackgroundColorHover: bbch,
+		Width:                app.convertLogicalToActualSizeX(bgTextRect.W / 2),
+		Height:               50,
+	})
+
+	// Load the quick save. Replaying the script removes the main menu
+	loadButton.OnClick(func() {
+		if err := app.loadGame("quick"); err != nil {
+			log.Println("[ERROR] Failed to load the game:", err)
+		}
+	})
+
 	quitValue, _ := gui.NewText(app.renderer, &gui.TextParams{
 		Value: "Quit",
 		Color: bc,
@@ -114,12 +139,16 @@ func (app *Application) initDraw() error {
 		sdl.PushEvent(&sdl.QuitEvent{Type: sdl.QUIT, Timestamp: sdl.GetTicks()})
 	})
 
+	// Load is only shown if there is a quick save to load
+	children := []gui.Widget{startButton}
+	if save.Exists(app.saveDir, "quick") {
+		children = append(children, loadButton)
+	}
+	children = append(children, quitButton)
+
 	menuList, _ := gui.NewList(app.renderer, &gui.ListParams{
-		Spacing: 20,
-		Children: []gui.Widget{
-			startButton,
-			quitButton,
-		},
+		Spacing:  20,
+		Children: children,
 	})
 
 	menu, _ := gui.NewPositioned(app.renderer, &gui.PositionedParams{
@@ -136,7 +165,7 @@ func (app *Application) initDraw() error {
 			Path: app.cfg.MainMenu.Background,
 			Origin: &Origin{
 				X: "left",
-				Y: "left",
+				Y: "top",
 			},
 			App: app,
 		})
@@ -183,8 +212,8 @@ func (app *Application) drawLoop() error {
 			bgTextRect.X = int32(resolution.X) - bgTextRect.W
 		}
 
-		if app.background != nil {
-			app.background.draw()
+		if err := app.drawScene(); err != nil {
+			return err
 		}
 
 		if app.background == nil && app.state == MENU_STATE {
@@ -210,6 +239,11 @@ func (app *Application) drawLoop() error {
 			pos.SetPosition(app.convertLogicalToActualX(bgTextRect.X+bgTextRect.W/2-bgTextRect.W/4), app.convertLogicalToActualY(bgTextRect.Y+bgTextRect.H/2)-pdo.H/2)
 		}
 
+		// Dialogs are placed again in every frame, so tweens can move them
+		if pos, ok := app.widgets["dialogPanel"].(*gui.Positioned); ok {
+			pos.SetPosition(app.convertLogicalToActualX(bgTextRect.X+bgTextRect.W/20+int32(app.dialogsX)), app.convertLogicalToActualY(bgTextRect.W/20+int32(app.dialogsY)))
+		}
+
 		for _, w := range app.widgets {
 			w1, _ := w.Draw()
 			gui.Render(app.renderer, w1)
@@ -232,11 +266,15 @@ func (app *Application) drawLoop() error {
 			app.splash = nil
 			if app.state == BOOT_STATE {
 				app.state = MENU_STATE
-			} else {
+			} else if app.state == SPLASH_STATE {
 				app.state = NOVEL_STATE
 			}
 		}
 	}
 
+	if app.state == ERROR_STATE {
+		return app.drawError()
+	}
+
 	return nil
 }
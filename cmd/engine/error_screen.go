@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/moheb2000/fufu/internal/gui"
+	"github.com/moheb2000/fufu/internal/script"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// showError logs the error and shows it in a full screen overlay that stops the story until the script is reloaded
+func (app *Application) showError(err error) {
+	details := errorDetails(err)
+	log.Println("[ERROR] Script error:", details)
+
+	app.hideError()
+	app.skip = false
+	app.auto = false
+	app.errorText = details
+	app.state = ERROR_STATE
+
+	resolution, rerr := app.getResolution()
+	if rerr != nil {
+		return
+	}
+
+	font := app.fm.GetFont("default", 16)
+	width := app.convertLogicalToActualSizeX(int32(resolution.X) * 9 / 10)
+
+	lines := []string{"Script error"}
+	var scriptErr *script.Error
+	if errors.As(err, &scriptErr) {
+		if scriptErr.Line > 0 {
+			lines = append(lines, fmt.Sprintf("%s:%d", scriptErr.File, scriptErr.Line))
+		} else if scriptErr.File != "" {
+			lines = append(lines, scriptErr.File)
+		}
+		lines = append(lines, scriptErr.Message)
+
+		if scriptErr.Traceback != "" {
+			lines = append(lines, scriptErr.Traceback)
+		}
+	} else {
+		lines = append(lines, err.Error())
+	}
+
+	texts, _ := gui.NewList(app.renderer, &gui.ListParams{
+		Spacing: 10,
+	})
+
+	// List adds every new widget at the top, so lines are added from the last one
+	for i := len(lines) - 1; i >= 0; i-- {
+		color := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+		if i == 0 {
+			color = sdl.Color{R: 255, G: 90, B: 90, A: 255}
+		}
+
+		text, _ := gui.NewText(app.renderer, &gui.TextParams{
+			Value: lines[i],
+			Color: color,
+			Font:  font,
+		})
+		texts.AddWidget(text)
+	}
+
+	limit, _ := gui.NewLimit(app.renderer, &gui.LimitParams{
+		Limit: int(width),
+		Child: texts,
+	})
+
+	scrollable, _ := gui.NewScrollableArea(app.renderer, &gui.ScrollableAreaParams{
+		H:     app.convertLogicalToActualSizeY(int32(resolution.Y) * 3 / 4),
+		Child: limit,
+	})
+
+	reloadButton := app.newErrorButton("Reload script", func() {
+		// Reloading replays the script, so it must not happen while the overlay is handling events
+		app.reloadRequested = true
+	})
+
+	copyButton := app.newErrorButton("Copy to clipboard", func() {
+		if err := sdl.SetClipboardText(app.errorText); err != nil {
+			log.Println("[ERROR] Failed to copy the error:", err)
+		}
+	})
+
+	content, _ := gui.NewList(app.renderer, &gui.ListParams{
+		Spacing: 20,
+		Children: []gui.Widget{
+			scrollable,
+			reloadButton,
+			copyButton,
+		},
+	})
+
+	app.errorScreen, _ = gui.NewPositioned(app.renderer, &gui.PositionedParams{
+		X:     app.convertLogicalToActualX(int32(resolution.X) / 20),
+		Y:     app.convertLogicalToActualY(int32(resolution.Y) / 20),
+		Child: content,
+	})
+}
+
+// newErrorButton returns a button for the error overlay
+func (app *Application) newErrorButton(value string, onClick func()) *gui.Button {
+	bc, _ := hexToSDLColor(app.cfg.MainMenu.Color)
+	bch, _ := hexToSDLColor(app.cfg.MainMenu.ColorHover)
+	bbc, _ := hexToSDLColor(app.cfg.MainMenu.BackgroundColor)
+	bbch, _ := hexToSDLColor(app.cfg.MainMenu.BackgroundColorHover)
+
+	text, _ := gui.NewText(app.renderer, &gui.TextParams{
+		Value: value,
+		Color: bc,
+		Font:  app.fm.GetFont("default", 16),
+	})
+
+	button, _ := gui.NewButton(app.renderer, &gui.ButtonParams{
+		Value:                text,
+		Color:                bc,
+		ColorHover:           bch,
+		BackgroundColor:      bbc,
+		BackgroundColorHover: bbch,
+		Width:                app.convertLogicalToActualSizeX(300),
+		Height:               50,
+	})
+	button.OnClick(onClick)
+
+	return button
+}
+
+// hideError removes the error overlay
+func (app *Application) hideError() {
+	if app.errorScreen != nil {
+		app.errorScreen.Destroy()
+		app.errorScreen = nil
+	}
+}
+
+// reloadScript reads the script files again and replays the story to the step that failed, so the fixed script continues from the same position
+func (app *Application) reloadScript() {
+	app.hideError()
+	app.loader.Clear()
+
+	// Errors of the replay are shown by replay itself
+	app.replay(app.story.steps, app.story.inputs)
+}
+
+// drawError draws the error overlay over everything
+func (app *Application) drawError() error {
+	resolution, err := app.getResolution()
+	if err != nil {
+		return err
+	}
+
+	app.renderer.SetDrawColor(10, 10, 10, 255)
+	app.renderer.FillRect(&sdl.Rect{X: 0, Y: 0, W: int32(resolution.X), H: int32(resolution.Y)})
+
+	if app.errorScreen != nil {
+		app.renderer.SetLogicalSize(0, 0)
+		do, _ := app.errorScreen.Draw()
+		gui.Render(app.renderer, do)
+		app.renderer.SetLogicalSize(int32(resolution.X), int32(resolution.Y))
+	}
+
+	return nil
+}
+
+// errorDetails returns the error with its lua traceback
+func errorDetails(err error) string {
+	var scriptErr *script.Error
+	if errors.As(err, &scriptErr) && scriptErr.Traceback != "" {
+		return scriptErr.Error() + "\n" + scriptErr.Traceback
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moheb2000/fufu/internal/config"
+	"github.com/moheb2000/fufu/internal/headless"
+)
+
+// runGraph runs every path of choices without a window and writes the graph of the story in DOT or JSON. Dead ends and unreachable lines are also printed to stderr
+func runGraph(args []string) int {
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	format := flags.String("format", "dot", "output format: dot or json")
+	output := flags.String("o", "", "output file. The graph is written to stdout if it's empty")
+	depth := flags.Int("depth", 8, "number of choices explored in every path of the story. The first option is chosen after them")
+	maxSteps := flags.Int("max-steps", headless.MAX_STEPS, "number of lines in a path before it's reported as a loop")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != "dot" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q. Use dot or json\n", *format)
+		return 2
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open the config file:", err)
+		return 1
+	}
+
+	params, cleanup, err := newHeadlessParams(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer cleanup()
+	params.MaxSteps = *maxSteps
+
+	g, err := headless.BuildGraph(params, *depth)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "json" {
+		err = g.WriteJSON(w)
+	} else {
+		err = g.WriteDOT(w)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	for _, d := range g.DeadEnds {
+		fmt.Fprintf(os.Stderr, "Dead end after choices %v at %s: %s\n", d.Choices, d.Node, d.Reason)
+	}
+
+	for _, n := range g.Nodes {
+		if n.Unreachable {
+			fmt.Fprintf(os.Stderr, "Unreachable %s at %s:%d\n", n.Kind, n.File, n.Line)
+		}
+	}
+
+	return 0
+}
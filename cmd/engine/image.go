@@ -0,0 +1,102 @@
+package main
+
+import (
+	"time"
+
+	"github.com/moheb2000/fufu/internal/frames"
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Image is a static image or an animated image of a JSON file. Animated images change their frame in the animation manager
+type Image struct {
+	// textures are the loaded images by their paths. A spritesheet has one texture for all of its frames
+	textures  map[string]*sdl.Texture
+	path      string
+	player    *frames.Player
+	destroyed bool
+}
+
+// loadImage loads the image of the path. Paths of JSON files are loaded as animated images and their frames start to change in the animation manager
+func (app *Application) loadImage(renderer *sdl.Renderer, path string) (*Image, error) {
+	i := Image{textures: map[string]*sdl.Texture{}, path: path}
+
+	if !frames.IsAnimation(path) {
+		texture, err := img.LoadTexture(renderer, path)
+		if err != nil {
+			return nil, err
+		}
+		i.textures[path] = texture
+
+		return &i, nil
+	}
+
+	animation, err := frames.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range animation.Paths() {
+		texture, err := img.LoadTexture(renderer, p)
+		if err != nil {
+			i.Destroy()
+			return nil, err
+		}
+		i.textures[p] = texture
+	}
+
+	i.player = frames.NewPlayer(animation)
+	app.am.AddAmbient(func(dt time.Duration) bool {
+		if i.destroyed {
+			return true
+		}
+
+		return i.player.Update(dt)
+	})
+
+	return &i, nil
+}
+
+// frame returns the texture of the current frame and the part of it that is the frame. The part is nil for the whole texture
+func (i *Image) frame() (*sdl.Texture, *sdl.Rect) {
+	if i.player == nil {
+		return i.textures[i.path], nil
+	}
+
+	f := i.player.Frame()
+	if f.Rect == nil {
+		return i.textures[f.Path], nil
+	}
+
+	return i.textures[f.Path], &sdl.Rect{X: int32(f.Rect.X), Y: int32(f.Rect.Y), W: int32(f.Rect.W), H: int32(f.Rect.H)}
+}
+
+// Size returns the size of the current frame
+func (i *Image) Size() (int32, int32, error) {
+	texture, src := i.frame()
+	if src != nil {
+		return src.W, src.H, nil
+	}
+
+	_, _, w, h, err := texture.Query()
+
+	return w, h, err
+}
+
+// Draw draws the current frame to dst with the opacity from 0 to 1
+func (i *Image) Draw(renderer *sdl.Renderer, dst *sdl.Rect, opacity float64) error {
+	texture, src := i.frame()
+
+	texture.SetBlendMode(sdl.BLENDMODE_BLEND)
+	texture.SetAlphaMod(uint8(opacity * 255))
+
+	return renderer.Copy(texture, src, dst)
+}
+
+func (i *Image) Destroy() {
+	i.destroyed = true
+
+	for _, texture := range i.textures {
+		texture.Destroy()
+	}
+}
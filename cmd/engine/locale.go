@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/moheb2000/fufu/internal/config"
+	"github.com/moheb2000/fufu/internal/locale"
+	"github.com/moheb2000/fufu/internal/script"
+)
+
+// loadLocale reads the string table of the language in settings. The game shows the texts of the script if the table can't be read
+func (app *Application) loadLocale() {
+	app.locale = nil
+	if app.settings.Language == "" {
+		return
+	}
+
+	table, err := locale.Load(filepath.Join(app.loader.Root(), locale.DIR), app.settings.Language)
+	if err != nil {
+		log.Println("[WARNING] Failed to load the string table of the language:", err)
+		return
+	}
+
+	app.locale = table
+}
+
+// runI18n runs the subcommands of translations. The returned value is the exit code of the engine
+func runI18n(args []string) int {
+	if len(args) == 0 || args[0] != "extract" {
+		fmt.Fprintln(os.Stderr, "Usage: fufu i18n extract [-dir locales] [language...]")
+		return 2
+	}
+
+	return runExtract(args[1:])
+}
+
+// runExtract writes the texts of the script to the template string table and adds new texts to the string tables of the languages
+func runExtract(args []string) int {
+	flags := flag.NewFlagSet("i18n extract", flag.ContinueOnError)
+	dir := flags.String("dir", locale.DIR, "directory of the string tables")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open the config file:", err)
+		return 1
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	entries, skipped, err := locale.Extract(script.NewLoader(root), cfg.Script)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	for _, s := range skipped {
+		fmt.Fprintln(os.Stderr, "[WARNING]", s)
+	}
+
+	template := locale.Table{}
+	template.Merge(entries)
+	if err := template.Write(filepath.Join(*dir, locale.TEMPLATE+".json")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// Tables of languages keep their translations and only get the new texts of the script
+	for _, language := range flags.Args() {
+		path := filepath.Join(*dir, language+".json")
+
+		table, err := locale.Read(path)
+		if os.IsNotExist(err) {
+			table = &locale.Table{Language: language}
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		table.Merge(entries)
+		if err := table.Write(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Extracted %d texts to %s\n", len(entries), *dir)
+
+	return 0
+}
@@ -3,6 +3,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/moheb2000/fufu/internal/config"
 )
@@ -15,6 +16,20 @@ func main() {
 		engineVersion = "undefined"
 	}
 
+	// Subcommands run in the terminal without opening a window
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		case "graph":
+			os.Exit(runGraph(os.Args[2:]))
+		case "test":
+			os.Exit(runTest(os.Args[2:]))
+		case "i18n":
+			os.Exit(runI18n(os.Args[2:]))
+		}
+	}
+
 	// Get engine configs from "config.json" file
 	cfg, err := config.Get()
 	if err != nil {
@@ -1,10 +1,11 @@
 package main
 
 import (
+	"log"
 	"time"
 
+	"github.com/moheb2000/fufu/internal/save"
 	"github.com/veandco/go-sdl2/sdl"
-	lua "github.com/yuin/gopher-lua"
 )
 
 // mainLoop runs the main loop of the engine
@@ -17,13 +18,92 @@ func (app *Application) mainLoop() error {
 
 		// Event loop
 		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+			// Only the error overlay can handle events while it's visible
+			if app.state == ERROR_STATE {
+				if _, ok := event.(*sdl.QuitEvent); ok {
+					running = false
+				}
+
+				if app.errorScreen != nil {
+					app.errorScreen.HandleEvent(event)
+				}
+
+				continue
+			}
+
 			switch e := event.(type) {
 			case *sdl.QuitEvent:
 				running = false
 			case *sdl.KeyboardEvent:
 				if e.Type == sdl.KEYUP {
 					if app.state == NOVEL_STATE && e.Keysym.Sym == sdl.K_SPACE {
-						app.lua.l.Resume(app.lua.co, app.lua.fn)
+						app.advance()
+					}
+
+					// Quick save and quick load
+					if (app.state == NOVEL_STATE || app.state == OPTIONS_STATE || app.state == INPUT_STATE) && e.Keysym.Sym == sdl.K_F5 {
+						if err := app.saveGame("quick"); err != nil {
+							log.Println("[ERROR] Failed to save the game:", err)
+						}
+
+						if err := app.seen.Flush(); err != nil {
+							log.Println("[ERROR] Failed to write read lines:", err)
+						}
+					}
+
+					if app.state != BOOT_STATE && e.Keysym.Sym == sdl.K_F9 && save.Exists(app.saveDir, "quick") {
+						if err := app.loadGame("quick"); err != nil {
+							log.Println("[ERROR] Failed to load the game:", err)
+						}
+					}
+
+					// Toggle auto mode
+					if (app.state == NOVEL_STATE || app.state == OPTIONS_STATE) && e.Keysym.Sym == sdl.K_a {
+						app.auto = !app.auto
+					}
+
+					// Change skip mode between skipping read lines and all lines
+					if (app.state == NOVEL_STATE || app.state == OPTIONS_STATE || app.state == WAIT_STATE) && e.Keysym.Sym == sdl.K_s {
+						app.settings.ToggleSkipMode()
+						if err := app.settings.Write(); err != nil {
+							log.Println("[ERROR] Failed to write the settings:", err)
+						}
+					}
+
+					// Change text speed. It's used from the next line
+					if app.state == NOVEL_STATE || app.state == OPTIONS_STATE || app.state == WAIT_STATE {
+						steps := 0
+						switch e.Keysym.Sym {
+						case sdl.K_EQUALS, sdl.K_KP_PLUS:
+							steps = 1
+						case sdl.K_MINUS, sdl.K_KP_MINUS:
+							steps = -1
+						}
+
+						if steps != 0 {
+							app.settings.ChangeTextSpeed(steps)
+							if err := app.settings.Write(); err != nil {
+								log.Println("[ERROR] Failed to write the settings:", err)
+							}
+						}
+					}
+
+					// Toggle skip mode
+					if (app.state == NOVEL_STATE || app.state == WAIT_STATE) && e.Keysym.Sym == sdl.K_TAB {
+						app.skip = !app.skip
+					}
+
+					if (app.state == NOVEL_STATE || app.state == OPTIONS_STATE || app.state == INPUT_STATE) && e.Keysym.Sym == sdl.K_PAGEUP {
+						if err := app.rollback(); err != nil {
+							log.Println("[ERROR] Failed to rollback:", err)
+						}
+					}
+				}
+			case *sdl.MouseWheelEvent:
+				// Scrolling up outside of the dialog panel rolls back the story
+				if (app.state == NOVEL_STATE || app.state == OPTIONS_STATE || app.state == INPUT_STATE) && e.Y > 0 && !app.isMouseOnDialogPanel() {
+					if err := app.rollback(); err != nil {
+						log.Println("[ERROR] Failed to rollback:", err)
 					}
 				}
 			}
@@ -33,14 +113,22 @@ func (app *Application) mainLoop() error {
 				widget.HandleEvent(event)
 			}
 
-			if app.state == OPTIONS_STATE && *app.result != 0 {
-				app.dialogs.RemoveLastWidget()
-				app.lua.l.Resume(app.lua.co, app.lua.fn, lua.LNumber(*app.result))
-				*app.result = 0
-				app.state = NOVEL_STATE
-			}
+			app.updateChoice()
+		}
+
+		app.updateDevMode()
+
+		if app.reloadRequested {
+			app.reloadRequested = false
+			app.reloadScript()
 		}
 
+		// Timed choices choose their default option in the animation manager
+		app.updateChoice()
+		app.updateSkip()
+		app.updateWait()
+		app.updateAuto()
+
 		// Draw loop
 		// Clear window with black color
 		app.renderer.SetDrawColor(0, 0, 0, 255)
@@ -69,3 +157,12 @@ func (app *Application) mainLoop() error {
 
 	return nil
 }
+
+// updateChoice resumes the script with the option that the player has chosen
+func (app *Application) updateChoice() {
+	if app.state == OPTIONS_STATE && *app.result != 0 {
+		result := *app.result
+		*app.result = 0
+		app.resume(result)
+	}
+}
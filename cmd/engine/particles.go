@@ -0,0 +1,183 @@
+package main
+
+import (
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/moheb2000/fufu/internal/particles"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Particles draws the particles of an emitter in the scene. Particles without a texture are drawn as rectangles of their color
+type Particles struct {
+	emitter   *particles.Emitter
+	image     *Image
+	color     sdl.Color
+	destroyed bool
+}
+
+// addParticles starts an emitter of the config. The emitter with the same name stops spawning, so its particles die out while the new emitter starts
+func (app *Application) addParticles(c *particles.Config) error {
+	if c.Name != "" {
+		app.stopParticles(c.Name)
+	}
+
+	p := Particles{emitter: particles.NewEmitter(*c, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))}
+	p.color, _ = hexToSDLColor(c.Color)
+
+	if c.Texture != "" {
+		image, err := app.loadImage(app.renderer, c.Texture)
+		if err != nil {
+			return err
+		}
+		p.image = image
+	}
+
+	scene, err := app.sceneRect()
+	if err != nil {
+		p.Destroy()
+		return err
+	}
+
+	if c.Warm {
+		p.emitter.Warm(float64(scene.W), float64(scene.H))
+	}
+
+	app.particles = append(app.particles, &p)
+	app.am.AddAmbient(func(dt time.Duration) bool {
+		if p.destroyed {
+			return true
+		}
+
+		scene, err := app.sceneRect()
+		if err != nil {
+			return false
+		}
+
+		p.emitter.Update(dt, float64(scene.W), float64(scene.H))
+		if p.emitter.Done() {
+			app.removeParticles(&p)
+			return true
+		}
+
+		return false
+	})
+
+	return nil
+}
+
+// stopParticles stops the emitters with the name. While replaying, their particles are removed at once, because nothing is drawn until the end of the replay
+func (app *Application) stopParticles(name string) {
+	for _, p := range slices.Clone(app.particles) {
+		if p.emitter.Config.Name != name {
+			continue
+		}
+
+		p.emitter.Stop()
+		if app.story.replaying {
+			app.removeParticles(p)
+		}
+	}
+}
+
+// removeParticles removes the emitter from the scene
+func (app *Application) removeParticles(p *Particles) {
+	app.particles = slices.DeleteFunc(app.particles, func(other *Particles) bool {
+		return other == p
+	})
+	p.Destroy()
+}
+
+// clearParticles removes all emitters from the scene
+func (app *Application) clearParticles() {
+	for _, p := range app.particles {
+		p.Destroy()
+	}
+	app.particles = nil
+}
+
+// drawParticles draws the particles of the layer in the scene area
+func (app *Application) drawParticles(area *sdl.Rect, layer string) error {
+	for _, p := range app.particles {
+		if p.emitter.Config.Layer != layer {
+			continue
+		}
+
+		if err := p.draw(app.renderer, area, app.camera, app.particleTexture); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// particleTexture returns a white pixel that is stretched and colored to draw particles without a texture
+func (app *Application) particleTexture() (*sdl.Texture, error) {
+	if app.pixel != nil {
+		return app.pixel, nil
+	}
+
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, 1, 1, 32, sdl.PIXELFORMAT_RGBA8888)
+	if err != nil {
+		return nil, err
+	}
+	defer surface.Free()
+
+	surface.FillRect(nil, 0xffffffff)
+
+	app.pixel, err = app.renderer.CreateTextureFromSurface(surface)
+
+	return app.pixel, err
+}
+
+// draw draws the particles centered at their positions through the camera. pixel returns the texture of particles without a texture
+func (p *Particles) draw(renderer *sdl.Renderer, area *sdl.Rect, camera *Camera, pixel func() (*sdl.Texture, error)) error {
+	var texture *sdl.Texture
+	var src *sdl.Rect
+	ratio := 1.0
+
+	if p.image != nil {
+		texture, src = p.image.frame()
+		w, h, err := p.image.Size()
+		if err != nil {
+			return err
+		}
+		ratio = float64(h) / float64(w)
+	} else {
+		var err error
+		texture, err = pixel()
+		if err != nil {
+			return err
+		}
+	}
+
+	texture.SetBlendMode(sdl.BLENDMODE_BLEND)
+	texture.SetColorMod(p.color.R, p.color.G, p.color.B)
+
+	for i := range p.emitter.Particles {
+		particle := &p.emitter.Particles[i]
+		w := p.emitter.Size(particle)
+		h := w * ratio * p.emitter.Config.Stretch
+
+		texture.SetAlphaMod(uint8(p.emitter.Alpha(particle) * 255))
+
+		dst := camera.apply(float64(area.X)+particle.X-w/2, float64(area.Y)+particle.Y-h/2, w, h, area)
+		dst.W = max(dst.W, 1)
+		dst.H = max(dst.H, 1)
+		renderer.CopyEx(texture, src, dst, particle.Rotation, nil, sdl.FLIP_NONE)
+	}
+
+	// The texture of particles without a texture is shared, so its color is set back
+	texture.SetColorMod(255, 255, 255)
+
+	return nil
+}
+
+func (p *Particles) Destroy() {
+	p.destroyed = true
+
+	if p.image != nil {
+		p.image.Destroy()
+	}
+}
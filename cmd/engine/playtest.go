@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/moheb2000/fufu/internal/config"
+	"github.com/moheb2000/fufu/internal/headless"
+)
+
+// runTest plays the story without a window with the choices of golden files and compares the transcripts with them. The returned value is the exit code of the engine
+func runTest(args []string) int {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	dir := flags.String("dir", "tests", "directory of golden files that are used if no file is given")
+	update := flags.Bool("update", false, "write the new transcripts to the golden files instead of comparing them")
+	choices := flags.String("choices", "", "comma separated choices like 1,2,1. The transcript is printed or written to the -create file")
+	create := flags.String("create", "", "golden file that is created with -choices")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open the config file:", err)
+		return 1
+	}
+
+	params, cleanup, err := newHeadlessParams(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer cleanup()
+
+	if *choices != "" {
+		return playChoices(params, *choices, *create)
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files, _ = filepath.Glob(filepath.Join(*dir, "*.golden"))
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintf(os.Stderr, "No golden files found in %s\n", *dir)
+		return 1
+	}
+
+	failed := 0
+	for _, file := range files {
+		if err := headless.CheckGolden(params, file, *update); err != nil {
+			failed++
+			fmt.Println("FAIL", err)
+			continue
+		}
+
+		fmt.Println("ok  ", file)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d playthroughs failed\n", failed, len(files))
+		return 1
+	}
+
+	return 0
+}
+
+// playChoices plays the story with the choices and prints the transcript or writes it to a new golden file
+func playChoices(params headless.Params, choices string, create string) int {
+	g := headless.Golden{}
+	for _, field := range strings.Split(choices, ",") {
+		choice, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid choice %q\n", field)
+			return 2
+		}
+		g.Choices = append(g.Choices, choice)
+	}
+
+	transcript, err := headless.Playthrough(params, g.Choices)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	g.Transcript = transcript
+
+	if create == "" {
+		fmt.Print(transcript)
+		return 0
+	}
+
+	if err := g.Write(create); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"github.com/moheb2000/fufu/internal/script"
+	"github.com/veandco/go-sdl2/sdl"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Snapshot keeps the state of the story at a yield point of the script. A coroutine can't be copied, so the script position is restored by replaying the steps before the snapshot. The dialog list, background and music are rebuilt by the replay and the lua globals are written back into the tables of the replay from the snapshot, so values that don't come from the script itself (like random numbers) stay the same
+type Snapshot struct {
+	// step is the number of steps before this yield point
+	step int
+	// yield is the name of the function that yielded the script, like "narrate" or "choice"
+	yield   string
+	music   MusicState
+	globals map[string]lua.LValue
+}
+
+// takeSnapshot records the current state of the story. It must be called after every yield of the script
+func (app *Application) takeSnapshot() {
+	globals := script.CopyGlobals(app.lua.l, app.lua.builtins)
+
+	app.story.snapshots = append(app.story.snapshots, Snapshot{
+		step:    len(app.story.steps),
+		yield:   app.story.yield,
+		music:   app.story.music,
+		globals: globals,
+	})
+}
+
+// rollback returns the story to the previous narrate, say, choice or input
+func (app *Application) rollback() error {
+	// The last snapshot is the current position of the story, so search the snapshots before it
+	for i := len(app.story.snapshots) - 2; i >= 0; i-- {
+		snapshot := app.story.snapshots[i]
+		if snapshot.yield != "narrate" && snapshot.yield != "say" && snapshot.yield != "choice" && snapshot.yield != "input" {
+			continue
+		}
+
+		return app.restoreSnapshot(snapshot)
+	}
+
+	return nil
+}
+
+// restoreSnapshot restarts the script from the snapshot
+func (app *Application) restoreSnapshot(snapshot Snapshot) error {
+	steps := make([]int, snapshot.step)
+	copy(steps, app.story.steps)
+
+	err := app.replay(steps, app.story.inputs)
+	if err != nil {
+		return err
+	}
+
+	// Music must be the same as the snapshot, but the replay may have changed it if the script doesn't run the same way every time
+	if app.story.music != snapshot.music {
+		app.story.music = snapshot.music
+		app.aum.StopMusic()
+
+		if snapshot.music.Path != "" {
+			app.aum.PlayMusic(snapshot.music.Path, snapshot.music.Loop)
+
+			if snapshot.music.Paused {
+				app.aum.PauseMusic()
+			}
+		}
+	}
+
+	script.RestoreGlobals(app.lua.l, snapshot.globals)
+
+	return nil
+}
+
+// isMouseOnDialogPanel checks if the mouse is over the dialog panel. Mouse wheel scrolls the dialogs there instead of rolling back the story
+func (app *Application) isMouseOnDialogPanel() bool {
+	resolution, err := app.getResolution()
+	if err != nil {
+		return false
+	}
+
+	w := int32(float64(resolution.X) * app.cfg.DialogPanel.Width)
+	var x int32
+	if app.cfg.DialogPanel.Direction == "right" {
+		x = int32(resolution.X) - w
+	}
+
+	mouseX, _, _ := sdl.GetMouseState()
+
+	return mouseX >= app.convertLogicalToActualX(x) && mouseX <= app.convertLogicalToActualX(x+w)
+}
@@ -1,17 +1,22 @@
 package main
 
 import (
+	"slices"
 	"time"
 
-	"github.com/veandco/go-sdl2/img"
+	"github.com/moheb2000/fufu/internal/ease"
+	"github.com/moheb2000/fufu/internal/tween"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 type Background struct {
 	backgroundParams *BackgroundParams
-	texture          *sdl.Texture
+	image            *Image
 	renderer         *sdl.Renderer
 	opacity          float64
+	// x and y move the background from its place. Scripts can change them with tween
+	x float64
+	y float64
 }
 
 type BackgroundParams struct {
@@ -31,86 +36,181 @@ func newBackground(renderer *sdl.Renderer, p *BackgroundParams) (*Background, er
 		renderer:         renderer,
 		opacity:          1,
 	}
-	bgTexture, err := img.LoadTexture(renderer, bg.backgroundParams.Path)
+	image, err := p.App.loadImage(renderer, bg.backgroundParams.Path)
 	if err != nil {
 		return nil, err
 	}
-	bg.texture = bgTexture
+	bg.image = image
 
 	return &bg, nil
 }
 
-func (bg *Background) draw() error {
-	resolution, err := bg.backgroundParams.App.getResolution()
+// draw draws the background with the camera. The image covers the scene and its origin decides which part of it is in the scene when the camera is in its place, so the camera can show the other parts
+func (bg *Background) draw(scene *sdl.Rect, camera *Camera) error {
+	tw, th, err := bg.image.Size()
 	if err != nil {
 		return err
 	}
 
-	// This is equivalent to bgTextRect.W
-	panelWidth := int32(float64(resolution.X) * bg.backgroundParams.App.cfg.DialogPanel.Width)
-
-	_, _, tw, th, _ := bg.texture.Query()
-	dw := int32(resolution.X) - panelWidth
-	dh := int32(resolution.Y)
+	scale := max(float64(scene.W)/float64(tw), float64(scene.H)/float64(th))
+	w := float64(tw) * scale
+	h := float64(th) * scale
 
-	var sw, sh int32
-	if tw/th >= dw/dh {
-		sw = th * dw / dh
-		sh = th
-	} else {
-		sw = tw
-		sh = tw * dh / dw
-	}
-
-	var x, y int32
-	if bg.backgroundParams.App.cfg.DialogPanel.Direction == "left" {
-		x = panelWidth
-	}
-
-	var xOffset, yOffset int32
+	var x, y float64
 	switch bg.backgroundParams.Origin.X {
-	case "left":
-		xOffset = 0
 	case "center":
-		xOffset = int32Abs(sw/2 - dw/2)
+		x = (float64(scene.W) - w) / 2
 	case "right":
-		xOffset = int32Abs(sw - dw)
+		x = float64(scene.W) - w
 	}
 
 	switch bg.backgroundParams.Origin.Y {
-	case "top":
-		yOffset = 0
 	case "center":
-		xOffset = int32Abs(sh/2 - dh/2)
+		y = (float64(scene.H) - h) / 2
 	case "bottom":
-		xOffset = int32Abs(sh - dh)
+		y = float64(scene.H) - h
 	}
 
-	bg.texture.SetBlendMode(sdl.BLENDMODE_BLEND)
-	bg.texture.SetAlphaMod(uint8(bg.opacity * 255))
-
-	return bg.renderer.Copy(bg.texture, &sdl.Rect{X: xOffset, Y: yOffset, W: sw, H: sh}, &sdl.Rect{X: x, Y: y, W: dw, H: dh})
+	return bg.image.Draw(bg.renderer, camera.apply(float64(scene.X)+x+bg.x, float64(scene.Y)+y+bg.y, w, h, scene), bg.opacity)
 }
 
-func (bg *Background) FadeIn() func(time.Duration) bool {
+// FadeIn returns a tween that can be played in animation manager to show the background from black in a second
+func (bg *Background) FadeIn() tween.Tween {
 	bg.opacity = 0
 
-	return func(dt time.Duration) bool {
-		if bg.opacity < 1 {
-			bg.opacity += dt.Seconds()
+	return tween.To(&bg.opacity, 1, time.Second, ease.Linear)
+}
+
+func (bg *Background) Destroy() {
+	if bg.image != nil {
+		bg.image.Destroy()
+	}
+}
+
+// sceneRect returns the part of the screen that the dialog panel doesn't cover. Background and sprites are drawn in it
+func (app *Application) sceneRect() (*sdl.Rect, error) {
+	resolution, err := app.getResolution()
+	if err != nil {
+		return nil, err
+	}
+
+	// This is equivalent to bgTextRect.W
+	panelWidth := int32(float64(resolution.X) * app.cfg.DialogPanel.Width)
+
+	scene := sdl.Rect{X: 0, Y: 0, W: int32(resolution.X) - panelWidth, H: int32(resolution.Y)}
+	if app.cfg.DialogPanel.Direction == "left" {
+		scene.X = panelWidth
+	}
+
+	return &scene, nil
+}
+
+// renderScene draws the background and the sprites over it to the scene texture with the camera. Particles are drawn under or over the sprites by their layer. Sprites are drawn in the order of their z and sprites with the same z in the order they are shown
+func (app *Application) renderScene() (*sdl.Texture, error) {
+	scene, err := app.sceneRect()
+	if err != nil {
+		return nil, err
+	}
+
+	// The scene texture is created again when the size of the scene changes
+	if app.sceneTexture != nil {
+		if _, _, w, h, _ := app.sceneTexture.Query(); w != scene.W || h != scene.H {
+			app.sceneTexture.Destroy()
+			app.sceneTexture = nil
 		}
+	}
+
+	if app.sceneTexture == nil {
+		app.sceneTexture, err = app.renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, scene.W, scene.H)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The window must be the target again, even if drawing the scene fails
+	app.renderer.SetRenderTarget(app.sceneTexture)
+	defer app.renderer.SetRenderTarget(nil)
+	app.renderer.SetDrawColor(0, 0, 0, 255)
+	app.renderer.Clear()
+
+	app.camera.update()
+
+	area := &sdl.Rect{X: 0, Y: 0, W: scene.W, H: scene.H}
+	if app.background != nil {
+		if err := app.background.draw(area, app.camera); err != nil {
+			return nil, err
+		}
+	}
 
-		// It will ensure opacity not exceed from 1
-		if bg.opacity > 1 {
-			bg.opacity = 1
+	if err := app.drawParticles(area, "back"); err != nil {
+		return nil, err
+	}
+
+	sprites := slices.Clone(app.sprites)
+	slices.SortStableFunc(sprites, func(a, b *Sprite) int {
+		return a.spriteParams.Show.Z - b.spriteParams.Show.Z
+	})
+
+	for _, s := range sprites {
+		if err := s.draw(area, app.camera); err != nil {
+			return nil, err
 		}
+	}
 
-		return bg.opacity == 1
+	if err := app.drawParticles(area, "front"); err != nil {
+		return nil, err
 	}
+
+	return app.sceneTexture, nil
 }
 
-func (bg *Background) Destroy() {
-	if bg.texture != nil {
-		bg.texture.Destroy()
+// drawScene draws the scene next to the dialog panel. While a transition is running, the transition draws the old and the new scene
+func (app *Application) drawScene() error {
+	if app.background == nil && len(app.sprites) == 0 && len(app.particles) == 0 && app.transition == nil {
+		return nil
+	}
+
+	scene, err := app.sceneRect()
+	if err != nil {
+		return err
+	}
+
+	texture, err := app.renderScene()
+	if err != nil {
+		return err
+	}
+
+	if app.transition != nil && app.transition.finished() {
+		app.finishTransition()
 	}
+
+	if app.transition != nil {
+		return app.transition.draw(texture, scene)
+	}
+
+	return app.renderer.Copy(texture, nil, scene)
+}
+
+// snapshotScene returns a copy of the scene as it is now, so a transition can draw it after the scene changes
+func (app *Application) snapshotScene() (*sdl.Texture, error) {
+	texture, err := app.renderScene()
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, w, h, err := texture.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := app.renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, w, h)
+	if err != nil {
+		return nil, err
+	}
+
+	app.renderer.SetRenderTarget(snapshot)
+	app.renderer.Copy(texture, nil, nil)
+	app.renderer.SetRenderTarget(nil)
+
+	return snapshot, nil
 }
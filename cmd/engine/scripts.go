@@ -1,37 +1,83 @@
 package main
 
 import (
+	"log"
+	"maps"
+	"math"
+	"slices"
 	"strconv"
 	"time"
 
+	"github.com/moheb2000/fufu/internal/ease"
 	"github.com/moheb2000/fufu/internal/gui"
+	"github.com/moheb2000/fufu/internal/markup"
+	"github.com/moheb2000/fufu/internal/particles"
+	"github.com/moheb2000/fufu/internal/script"
+	"github.com/moheb2000/fufu/internal/tween"
 	"github.com/veandco/go-sdl2/sdl"
 	lua "github.com/yuin/gopher-lua"
 )
 
 func (app *Application) initScript() error {
-	// Start lua VM and compiler
-	app.lua.l = lua.NewState()
+	app.lua.runner = nil
+
+	// Start lua VM and compiler. In sandbox mode, scripts can only access files with fufu.fs
+	app.lua.l = script.NewState(app.cfg.Sandbox)
+	script.OpenFS(app.lua.l, app.loader.Root(), app.saveDir)
 	app.lua.l.SetGlobal("get_engine_version", app.lua.l.NewFunction(getEngineVersion))
 	app.lua.l.SetGlobal("get_game_version", app.lua.l.NewFunction(app.getGameVersion))
-	app.lua.l.SetGlobal("font", app.lua.l.NewFunction(font))
+	app.lua.l.SetGlobal("font", app.lua.l.NewFunction(app.font))
 	app.lua.l.SetGlobal("character", app.lua.l.NewFunction(character))
 	app.lua.l.SetGlobal("narrate", app.lua.l.NewFunction(app.narrate))
 	app.lua.l.SetGlobal("say", app.lua.l.NewFunction(app.say))
 	app.lua.l.SetGlobal("choice", app.lua.l.NewFunction(app.choice))
+	app.lua.l.SetGlobal("input", app.lua.l.NewFunction(app.input))
 	app.lua.l.SetGlobal("bg", app.lua.l.NewFunction(app.bg))
+	app.lua.l.SetGlobal("show", app.lua.l.NewFunction(app.show))
+	app.lua.l.SetGlobal("hide", app.lua.l.NewFunction(app.hide))
 	app.lua.l.SetGlobal("splash", app.lua.l.NewFunction(app.sp))
+	app.lua.l.SetGlobal("wait", app.lua.l.NewFunction(app.wait))
+	app.lua.l.SetGlobal("tween", app.lua.l.NewFunction(app.tween))
+	app.lua.l.SetGlobal("wait_for_animations", app.lua.l.NewFunction(app.waitForAnimations))
+	app.lua.l.SetGlobal("shake", app.lua.l.NewFunction(app.shake))
+	app.lua.l.SetGlobal("zoom", app.lua.l.NewFunction(app.zoom))
+	app.lua.l.SetGlobal("pan", app.lua.l.NewFunction(app.pan))
+	app.lua.l.SetGlobal("particles", app.lua.l.NewFunction(app.particlesFn))
+	app.lua.l.SetGlobal("weather", app.lua.l.NewFunction(app.weather))
+	app.lua.l.SetGlobal("stop_particles", app.lua.l.NewFunction(app.stopParticlesFn))
 	app.lua.l.SetGlobal("play_music", app.lua.l.NewFunction(app.playMusic))
 	app.lua.l.SetGlobal("stop_music", app.lua.l.NewFunction(app.stopMusic))
 	app.lua.l.SetGlobal("pause_music", app.lua.l.NewFunction(app.pauseMusic))
 	app.lua.l.SetGlobal("resume_music", app.lua.l.NewFunction(app.resumeMusic))
 	app.lua.l.SetGlobal("play_sound", app.lua.l.NewFunction(app.playSound))
-	fn, err := app.lua.l.LoadFile("main.lua")
+	app.lua.l.SetGlobal("play_voice", app.lua.l.NewFunction(app.playVoice))
+
+	// Add label, jump and call for moving between parts of the story
+	flow, err := script.OpenFlow(app.lua.l)
+	if err != nil {
+		return err
+	}
+
+	// Add require and include for loading other script files of the game
+	err = app.loader.Open(app.lua.l)
+	if err != nil {
+		return err
+	}
+
+	fn, err := app.loader.Load(app.lua.l, app.cfg.Script)
+	if err != nil {
+		return err
+	}
+	app.lua.runner, err = script.NewRunner(app.lua.l, flow, fn)
 	if err != nil {
 		return err
 	}
-	app.lua.fn = fn
-	app.lua.co, _ = app.lua.l.NewThread()
+
+	// Globals that exist before running the script are not part of the story state
+	app.lua.builtins = make(map[string]bool)
+	app.lua.l.G.Global.ForEach(func(k, _ lua.LValue) {
+		app.lua.builtins[k.String()] = true
+	})
 
 	return nil
 }
@@ -53,11 +99,25 @@ func character(L *lua.LState) int {
 	L.SetField(c, "name", L.Get(1).(lua.LString))
 	L.SetField(c, "color", L.Get(2).(lua.LString))
 
+	// Sprites of the expressions are kept in the character, so show and say can find them
+	if properties := L.ToTable(3); properties != nil {
+		if sprites, ok := properties.RawGetString("sprites").(*lua.LTable); ok {
+			L.SetField(c, "sprites", sprites)
+		}
+	}
+
 	L.Push(c)
 	return 1
 }
 
-func font(L *lua.LState) int {
+func (app *Application) font(L *lua.LState) int {
+	// Fonts are recorded by their names, so {font} tags of texts can use them
+	if name, ok := L.Get(1).(lua.LString); ok {
+		if path, ok := L.Get(2).(lua.LString); ok {
+			app.fm.AddFont(string(name), string(path))
+		}
+	}
+
 	f := L.NewTable()
 	L.SetField(f, "name", L.Get(1).(lua.LString))
 	L.SetField(f, "path", L.Get(2).(lua.LString))
@@ -102,18 +162,23 @@ func (app *Application) narrate(L *lua.LState) int {
 		}
 	}
 
-	// Create a text widget
+	// Create a text widget. Read lines are recorded with the text of the script, so they don't change with the language
 	tw, _ := gui.NewText(app.renderer, &gui.TextParams{
-		Value: text,
+		Value: app.locale.Translate(text),
 		Color: color,
 		Font:  font,
+		Fonts: app.fm,
 	})
 
 	// Add new widget to dialogs list
 	app.dialogs.AddWidget(tw)
-	app.am.Add(tw.FadeIn())
+	if !app.story.replaying {
+		app.reveal(tw)
+	}
 
-	return L.Yield(lua.LNil)
+	app.readLine(L, "", text)
+
+	return app.yield(L, "narrate")
 }
 
 func (app *Application) say(L *lua.LState) int {
@@ -157,6 +222,11 @@ func (app *Application) say(L *lua.LState) int {
 		if fs, ok := properties.RawGetString("font_size").(lua.LNumber); ok {
 			fontSize = int(fs)
 		}
+
+		// The expression changes the sprite of the character if it's shown
+		if expression, ok := properties.RawGetString("expr").(lua.LString); ok && charTable != nil {
+			app.setExpression(L, "say", charTable, string(expression))
+		}
 	}
 
 	if fontPath != "" {
@@ -166,15 +236,17 @@ func (app *Application) say(L *lua.LState) int {
 	}
 
 	cw, _ := gui.NewText(app.renderer, &gui.TextParams{
-		Value: char,
+		Value: app.locale.Translate(char),
 		Color: charColor,
 		Font:  font,
+		Fonts: app.fm,
 	})
 
 	tw, _ := gui.NewText(app.renderer, &gui.TextParams{
-		Value: text,
+		Value: app.locale.Translate(text),
 		Color: textColor,
 		Font:  font,
+		Fonts: app.fm,
 	})
 
 	dw, _ := gui.NewDialog(app.renderer, &gui.DialogParams{
@@ -183,10 +255,14 @@ func (app *Application) say(L *lua.LState) int {
 	})
 
 	app.dialogs.AddWidget(dw)
-	app.am.Add(cw.FadeIn())
-	app.am.Add(tw.FadeIn())
+	if !app.story.replaying {
+		app.am.Play("", cw.FadeIn())
+		app.reveal(tw)
+	}
+
+	app.readLine(L, char, text)
 
-	return L.Yield(lua.LNil)
+	return app.yield(L, "say")
 }
 
 func (app *Application) choice(L *lua.LState) int {
@@ -194,16 +270,32 @@ func (app *Application) choice(L *lua.LState) int {
 	options := L.ToTable(1)
 	properties := L.ToTable(2)
 	color, _ := hexToSDLColor(app.cfg.DefaultTextColor)
+	disabledColor, _ := hexToSDLColor("#808080")
+	highlightColor, _ := hexToSDLColor("#ffd700")
 	font := app.fm.GetFont("default", 16)
 	fontName := "default"
 	fontPath := ""
 	fontSize := 16
 
+	// Conditions of the options are called before anything is shown
+	c, problems := script.ReadChoice(L, options, properties)
+	if len(problems) > 0 {
+		L.RaiseError("choice: %s", problems[0])
+	}
+
 	if properties != nil {
 		if sc, ok := properties.RawGetString("text_color").(lua.LString); ok {
 			color, _ = hexToSDLColor(string(sc))
 		}
 
+		if dc, ok := properties.RawGetString("disabled_color").(lua.LString); ok {
+			disabledColor, _ = hexToSDLColor(string(dc))
+		}
+
+		if hc, ok := properties.RawGetString("highlight_color").(lua.LString); ok {
+			highlightColor, _ = hexToSDLColor(string(hc))
+		}
+
 		if ft, ok := properties.RawGetString("font").(*lua.LTable); ok {
 			if fn, ok := ft.RawGetString("name").(lua.LString); ok {
 				fontName = string(fn)
@@ -229,75 +321,283 @@ func (app *Application) choice(L *lua.LState) int {
 		Spacing: 5,
 	})
 
-	for i := 1; i <= options.Len(); i++ {
+	// Hidden options don't have a number, so the number keys of the shown options don't skip any number
+	var choices []gui.Option
+	for _, option := range c.Options {
+		if !option.Visible {
+			continue
+		}
+
+		textColor := color
+		if !option.Enabled {
+			textColor = disabledColor
+		}
+
 		text, _ := gui.NewText(app.renderer, &gui.TextParams{
-			Value: strconv.Itoa(i) + "- " + string(options.RawGetInt(i).(lua.LString)),
-			Color: color,
+			Value: strconv.Itoa(len(choices)+1) + "- " + app.locale.Translate(option.Text),
+			Color: textColor,
 			Font:  font,
+			Fonts: app.fm,
 		})
 
+		var tooltip *gui.Text
+		if option.Tooltip != "" {
+			tooltip, _ = gui.NewText(app.renderer, &gui.TextParams{
+				Value: "{i}" + markup.Escape(app.locale.Translate(option.Tooltip)) + "{/}",
+				Color: disabledColor,
+				Font:  font,
+				Fonts: app.fm,
+			})
+		}
+
 		list.AddWidget(text)
-		app.am.Add(text.FadeIn())
+		if !app.story.replaying {
+			app.am.Play("", text.FadeIn())
+		}
+
+		choices = append(choices, gui.Option{
+			Text:     text,
+			Value:    option.Index,
+			Disabled: !option.Enabled,
+			Tooltip:  tooltip,
+		})
+	}
 
-		app.state = OPTIONS_STATE
+	var countdown *gui.Text
+	if c.Timeout > 0 {
+		countdown, _ = gui.NewText(app.renderer, &gui.TextParams{
+			Value: strconv.Itoa(int(math.Ceil(c.Timeout.Seconds()))),
+			Color: highlightColor,
+			Font:  font,
+		})
 	}
 
 	ops, _ := gui.NewOptions(app.renderer, &gui.OptionsParams{
-		Options: list,
-		Result:  app.result,
+		Options:        list,
+		Result:         app.result,
+		Choices:        choices,
+		HighlightColor: highlightColor,
+		Timeout:        c.Timeout,
+		Default:        c.Default,
+		Countdown:      countdown,
 	})
 
 	app.dialogs.AddWidget(ops)
+	app.state = OPTIONS_STATE
+
+	// Replaying doesn't wait for the player, so the time of the choice only runs in the game
+	if c.Timeout > 0 && !app.story.replaying {
+		app.am.Add(ops.Timer())
+	}
+
+	return app.yield(L, "choice")
+}
 
-	L.Push(lua.LNil)
+func (app *Application) input(L *lua.LState) int {
+	// Get function arguments
+	prompt := L.ToString(1)
+	properties := L.ToTable(2)
+	color, _ := hexToSDLColor(app.cfg.DefaultTextColor)
+	font := app.fm.GetFont("default", 16)
 
-	return L.Yield(lua.LNumber(1))
+	in, problems := script.ReadInput(properties)
+	if len(problems) > 0 {
+		L.RaiseError("input: %s", problems[0])
+	}
+
+	if properties != nil {
+		if sc, ok := properties.RawGetString("text_color").(lua.LString); ok {
+			color, _ = hexToSDLColor(string(sc))
+		}
+	}
+
+	pw, _ := gui.NewText(app.renderer, &gui.TextParams{
+		Value: app.locale.Translate(prompt),
+		Color: color,
+		Font:  font,
+		Fonts: app.fm,
+	})
+
+	ti, _ := gui.NewTextInput(app.renderer, &gui.TextInputParams{
+		Prompt:    pw,
+		Color:     color,
+		Font:      font,
+		Default:   in.Default,
+		MaxLength: in.MaxLength,
+		Allowed:   in.Allows,
+	})
+	ti.OnSubmit(app.submitInput)
+
+	app.dialogs.AddWidget(ti)
+	app.state = INPUT_STATE
+	if !app.story.replaying {
+		app.am.Add(ti.Blink())
+	}
+
+	return app.yield(L, "input")
 }
 
 func (app *Application) bg(L *lua.LState) int {
 	path := L.ToString(1)
 	properties := L.ToTable(2)
 	fade := false
-	originX := "left"
-	originY := "left"
+
+	origin, problems := script.ReadOrigin(properties)
+	if len(problems) > 0 {
+		L.RaiseError("bg: %s", problems[0])
+	}
 
 	if properties != nil {
-		if ox, ok := properties.RawGetString("originx").(lua.LString); ok {
-			originX = string(ox)
+		if f, ok := properties.RawGetString("fade").(lua.LBool); ok {
+			fade = bool(f)
 		}
+	}
 
-		if oy, ok := properties.RawGetString("originy").(lua.LString); ok {
-			originX = string(oy)
+	transition := app.readTransition(L, "bg", properties)
+
+	app.transit(transition, func() {
+		if app.background != nil {
+			app.background.Destroy()
+			app.background = nil
 		}
 
-		if f, ok := properties.RawGetString("fade").(lua.LBool); ok {
-			fade = bool(f)
+		background, _ := newBackground(app.renderer, &BackgroundParams{
+			Path: path,
+			Origin: &Origin{
+				X: origin.X,
+				Y: origin.Y,
+			},
+			App: app,
+		})
+		app.background = background
+	})
+
+	if fade && !app.story.replaying {
+		app.am.Play("", app.background.FadeIn())
+	}
+
+	return app.yield(L, "bg")
+}
+
+// show shows the sprite of a character expression over the background. If the character is already shown, its sprite is changed and properties that are not set stay the same
+func (app *Application) show(L *lua.LState) int {
+	c, problems := script.ReadCharacter(L.ToTable(1))
+	if len(problems) > 0 {
+		L.RaiseError("show: %s", problems[0])
+	}
+
+	expression := script.DEFAULT_EXPRESSION
+	base := script.Show{At: script.POSITIONS["center"]}
+	sprite := app.findSprite(c.Name)
+	if sprite != nil {
+		expression = sprite.spriteParams.Expression
+		base = sprite.spriteParams.Show
+	}
+
+	if e, ok := L.Get(2).(lua.LString); ok {
+		expression = string(e)
+	}
+
+	properties := L.ToTable(3)
+	show, problems := script.ReadShow(properties, base)
+	if len(problems) > 0 {
+		L.RaiseError("show: %s", problems[0])
+	}
+
+	path, ok := c.Sprites[expression]
+	if !ok {
+		L.RaiseError("show: character %q has no sprite for expression %q", c.Name, expression)
+	}
+
+	transition := app.readTransition(L, "show", properties)
+
+	app.transit(transition, func() {
+		if sprite != nil {
+			if err := sprite.setExpression(expression, path); err != nil {
+				log.Println("[ERROR] Failed to load the sprite:", err)
+			}
+			sprite.spriteParams.Show = *show
+
+			return
 		}
+
+		sprite, err := newSprite(app.renderer, &SpriteParams{
+			Character:  c.Name,
+			Expression: expression,
+			Path:       path,
+			Show:       *show,
+			App:        app,
+		})
+		if err != nil {
+			log.Println("[ERROR] Failed to load the sprite:", err)
+			return
+		}
+		app.sprites = append(app.sprites, sprite)
+	})
+
+	return 0
+}
+
+// hide removes the sprite of a character from the scene
+func (app *Application) hide(L *lua.LState) int {
+	c, problems := script.ReadCharacter(L.ToTable(1))
+	if len(problems) > 0 {
+		L.RaiseError("hide: %s", problems[0])
 	}
 
-	if app.background != nil {
-		app.background.Destroy()
-		app.background = nil
+	transition := app.readTransition(L, "hide", L.ToTable(2))
+
+	// Hiding a character that isn't shown doesn't change the scene, so there is nothing to transit
+	if app.findSprite(c.Name) == nil {
+		return 0
 	}
 
-	background, _ := newBackground(app.renderer, &BackgroundParams{
-		Path: path,
-		Origin: &Origin{
-			X: originX,
-			Y: originY,
-		},
-		App: app,
+	app.transit(transition, func() {
+		app.removeSprite(c.Name)
 	})
-	app.background = background
 
-	if fade {
-		app.am.Add(app.background.FadeIn())
+	return 0
+}
+
+// readTransition reads the transition property of bg, show or hide
+func (app *Application) readTransition(L *lua.LState, fn string, properties *lua.LTable) *script.Transition {
+	if properties == nil {
+		return nil
 	}
 
-	return L.Yield(lua.LNil)
+	transition, problems := script.ReadTransition(properties.RawGetString("transition"))
+	if len(problems) > 0 {
+		L.RaiseError("%s: %s", fn, problems[0])
+	}
+
+	return transition
+}
+
+// setExpression changes the sprite of a shown character to the expression
+func (app *Application) setExpression(L *lua.LState, fn string, charTable *lua.LTable, expression string) {
+	c, problems := script.ReadCharacter(charTable)
+	if len(problems) > 0 {
+		L.RaiseError("%s: %s", fn, problems[0])
+	}
+
+	path, ok := c.Sprites[expression]
+	if !ok {
+		L.RaiseError("%s: character %q has no sprite for expression %q", fn, c.Name, expression)
+	}
+
+	if sprite := app.findSprite(c.Name); sprite != nil {
+		if err := sprite.setExpression(expression, path); err != nil {
+			log.Println("[ERROR] Failed to load the sprite:", err)
+		}
+	}
 }
 
 func (app *Application) sp(L *lua.LState) int {
+	// Splash screens are skipped while replaying
+	if app.story.replaying {
+		return app.yield(L, "splash")
+	}
+
 	path := L.ToString(1)
 	color, _ := hexToSDLColor(string(L.ToString(2)))
 	duration := L.ToInt(3)
@@ -311,32 +611,191 @@ func (app *Application) sp(L *lua.LState) int {
 	app.splash = splash
 	app.state = SPLASH_STATE
 
-	return L.Yield(lua.LNil)
+	return app.yield(L, "splash")
+}
+
+// wait pauses the script for the duration in milliseconds
+func (app *Application) wait(L *lua.LState) int {
+	duration, problems := script.ReadWait(L.Get(1))
+	if len(problems) > 0 {
+		L.RaiseError("wait: %s", problems[0])
+	}
+
+	if !app.story.replaying {
+		app.waiting = app.am.Play("wait", tween.Delay(duration))
+		app.state = WAIT_STATE
+	}
+
+	return app.yield(L, "wait")
+}
+
+// tween changes the properties of a sprite, the background or the dialogs to new values over the duration in milliseconds. It doesn't pause the script, so wait_for_animations can be used to wait for it
+func (app *Application) tween(L *lua.LState) int {
+	t, problems := script.ReadTween(L.Get(1), L.Get(2), L.Get(3), L.Get(4))
+	if len(problems) > 0 {
+		L.RaiseError("tween: %s", problems[0])
+	}
+
+	easing, _ := ease.Lookup(t.Easing)
+	for _, property := range slices.Sorted(maps.Keys(t.Properties)) {
+		v, name := app.tweenValue(t, property)
+		if v == nil {
+			L.RaiseError("tween: %s", name)
+		}
+
+		app.playTween(name, tween.To(v, t.Properties[property], t.Duration, easing))
+	}
+
+	return 0
+}
+
+// shake shakes the scene by intensity pixels for the duration in milliseconds
+func (app *Application) shake(L *lua.LState) int {
+	s, problems := script.ReadShake(L.Get(1), L.Get(2))
+	if len(problems) > 0 {
+		L.RaiseError("shake: %s", problems[0])
+	}
+
+	// The shake gets weaker until it stops
+	app.camera.shake = s.Intensity
+	app.playTween("camera:shake", tween.To(&app.camera.shake, 0, s.Duration, ease.OutQuad))
+
+	return 0
+}
+
+// zoom scales the scene by the factor around the focus in the duration in milliseconds
+func (app *Application) zoom(L *lua.LState) int {
+	z, problems := script.ReadZoom(L.Get(1), L.Get(2), L.ToTable(3))
+	if len(problems) > 0 {
+		L.RaiseError("zoom: %s", problems[0])
+	}
+
+	focusX, focusY := z.FocusX, z.FocusY
+	if z.Character != "" {
+		sprite := app.findSprite(z.Character)
+		if sprite == nil {
+			L.RaiseError("zoom: character %q is not shown", z.Character)
+		}
+
+		scene, _ := app.sceneRect()
+		area := &sdl.Rect{X: 0, Y: 0, W: scene.W, H: scene.H}
+		if r, err := sprite.rect(area); err == nil {
+			focusX = (float64(r.X) + float64(r.W)/2) / float64(area.W)
+			focusY = (float64(r.Y) + float64(r.H)/2) / float64(area.H)
+		}
+	}
+
+	easing, _ := ease.Lookup(z.Easing)
+	app.playTween("camera:zoom", tween.Parallel(
+		tween.To(&app.camera.zoom, z.Factor, z.Duration, easing),
+		tween.To(&app.camera.focusX, focusX, z.Duration, easing),
+		tween.To(&app.camera.focusY, focusY, z.Duration, easing),
+	))
+
+	return 0
+}
+
+// pan moves the camera by pixels from its place in the duration in milliseconds
+func (app *Application) pan(L *lua.LState) int {
+	p, problems := script.ReadPan(L.Get(1), L.Get(2), L.Get(3), L.ToTable(4))
+	if len(problems) > 0 {
+		L.RaiseError("pan: %s", problems[0])
+	}
+
+	easing, _ := ease.Lookup(p.Easing)
+	app.playTween("camera:pan", tween.Parallel(
+		tween.To(&app.camera.x, p.X, p.Duration, easing),
+		tween.To(&app.camera.y, p.Y, p.Duration, easing),
+	))
+
+	return 0
+}
+
+// waitForAnimations pauses the script until all running animations are finished
+func (app *Application) waitForAnimations(L *lua.LState) int {
+	if !app.story.replaying {
+		app.waiting = nil
+		app.state = WAIT_STATE
+	}
+
+	return app.yield(L, "wait_for_animations")
+}
+
+// particlesFn starts an emitter of particles in the scene
+func (app *Application) particlesFn(L *lua.LState) int {
+	c, problems := script.ReadParticles(L.ToTable(1))
+	if len(problems) > 0 {
+		L.RaiseError("particles: %s", problems[0])
+	}
+
+	if err := app.addParticles(c); err != nil {
+		log.Println("[ERROR] Failed to start the particles:", err)
+	}
+
+	return 0
+}
+
+// weather starts a weather preset like "rain" with its intensity. "clear" stops the weather
+func (app *Application) weather(L *lua.LState) int {
+	c, problems := script.ReadWeather(L.Get(1), L.ToTable(2))
+	if len(problems) > 0 {
+		L.RaiseError("weather: %s", problems[0])
+	}
+
+	if c == nil {
+		app.stopParticles(particles.WEATHER_NAME)
+		return 0
+	}
+
+	if err := app.addParticles(c); err != nil {
+		log.Println("[ERROR] Failed to start the weather:", err)
+	}
+
+	return 0
+}
+
+// stopParticlesFn stops the emitter with the name. Its particles die out at the end of their lifetime
+func (app *Application) stopParticlesFn(L *lua.LState) int {
+	app.stopParticles(L.CheckString(1))
+
+	return 0
 }
 
 func (app *Application) playMusic(L *lua.LState) int {
 	path := L.ToString(1)
 	loop := L.ToBool(2)
 
-	app.aum.PlayMusic(path, loop)
+	app.story.music = MusicState{Path: path, Loop: loop}
+	if !app.story.replaying {
+		app.aum.PlayMusic(path, loop)
+	}
 
 	return 0
 }
 
 func (app *Application) stopMusic(L *lua.LState) int {
-	app.aum.StopMusic()
+	app.story.music = MusicState{}
+	if !app.story.replaying {
+		app.aum.StopMusic()
+	}
 
 	return 0
 }
 
 func (app *Application) pauseMusic(L *lua.LState) int {
-	app.aum.PauseMusic()
+	app.story.music.Paused = true
+	if !app.story.replaying {
+		app.aum.PauseMusic()
+	}
 
 	return 0
 }
 
 func (app *Application) resumeMusic(L *lua.LState) int {
-	app.aum.ResumeMusic()
+	app.story.music.Paused = false
+	if !app.story.replaying {
+		app.aum.ResumeMusic()
+	}
 
 	return 0
 }
@@ -344,7 +803,19 @@ func (app *Application) resumeMusic(L *lua.LState) int {
 func (app *Application) playSound(L *lua.LState) int {
 	path := L.ToString(1)
 
-	app.aum.PlaySound(path)
+	if !app.story.replaying {
+		app.aum.PlaySound(path)
+	}
+
+	return 0
+}
+
+func (app *Application) playVoice(L *lua.LState) int {
+	path := L.ToString(1)
+
+	if !app.story.replaying {
+		app.aum.PlayVoice(path)
+	}
 
 	return 0
 }
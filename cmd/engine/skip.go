@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moheb2000/fufu/internal/markup"
+	"github.com/moheb2000/fufu/internal/save"
+	"github.com/veandco/go-sdl2/sdl"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// readLine marks the line as read and remembers if the player had read it before, so skip mode can decide to skip it or not
+func (app *Application) readLine(L *lua.LState, char string, text string) {
+	// Where returns something like "main.lua:12:" and only the file name is needed for the id
+	file, _, _ := strings.Cut(L.Where(1), ":")
+	id := text
+	if char != "" {
+		id = char + "\x00" + text
+	}
+	id = save.LineID(file, id)
+
+	app.story.lineRead = app.seen.Has(id)
+	app.story.lineLength = utf8.RuneCountInString(markup.Plain(text))
+	app.seen.Add(id)
+}
+
+// updateSkip resumes the script while skip mode is active. Skip mode is active while holding ctrl or after toggling it with tab, and it stops at choices and unread lines
+func (app *Application) updateSkip() {
+	if !app.skipping() {
+		return
+	}
+
+	if app.storyFinished() || app.state == OPTIONS_STATE {
+		app.skip = false
+		return
+	}
+
+	if app.state != NOVEL_STATE {
+		return
+	}
+
+	unread := (app.story.yield == "narrate" || app.story.yield == "say") && !app.story.lineRead
+	if unread && app.settings.SkipMode != save.SKIP_ALL {
+		app.skip = false
+		return
+	}
+
+	app.resume(0)
+}
+
+// skipping checks if skip mode is on or the player holds Ctrl to skip
+func (app *Application) skipping() bool {
+	return app.skip || sdl.GetModState()&sdl.KMOD_CTRL != 0
+}
@@ -3,16 +3,18 @@ package main
 import (
 	"time"
 
-	"github.com/veandco/go-sdl2/img"
+	"github.com/moheb2000/fufu/internal/ease"
+	"github.com/moheb2000/fufu/internal/tween"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 type Splash struct {
 	renderer     *sdl.Renderer
 	splashParams *SplashParams
-	texture      *sdl.Texture
+	image        *Image
 	opacity      float64
-	timer        time.Time
+	// handle is the tween that fades the splash in, waits for its duration and fades it out
+	handle *tween.Handle
 }
 
 type SplashParams struct {
@@ -29,13 +31,17 @@ func newSplash(renderer *sdl.Renderer, p *SplashParams) (*Splash, error) {
 		opacity:      1,
 	}
 
-	splashTexture, err := img.LoadTexture(renderer, s.splashParams.Path)
+	image, err := p.App.loadImage(renderer, s.splashParams.Path)
 	if err != nil {
 		return nil, err
 	}
-	s.texture = splashTexture
+	s.image = image
 
-	s.splashParams.App.am.Add(s.FadeIn())
+	s.handle = s.splashParams.App.am.Play("splash", tween.Sequence(
+		s.FadeIn(),
+		tween.Delay(s.splashParams.Duration),
+		s.FadeOut(),
+	))
 
 	return &s, nil
 }
@@ -46,63 +52,33 @@ func (s *Splash) draw() (bool, error) {
 		return true, err
 	}
 
-	if s.opacity == 1 && s.timer.IsZero() {
-		s.timer = time.Now()
-	}
-
-	if s.opacity == 1 && time.Since(s.timer) > s.splashParams.Duration {
-		s.splashParams.App.am.Add(s.FadeOut())
-	}
-
 	s.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
-	s.texture.SetBlendMode(sdl.BLENDMODE_BLEND)
-	s.texture.SetAlphaMod(uint8(s.opacity * 255))
 
 	s.renderer.SetDrawColor(s.splashParams.Color.R, s.splashParams.Color.G, s.splashParams.Color.B, uint8(s.opacity*255))
 	s.renderer.FillRect(&sdl.Rect{X: 0, Y: 0, W: int32(resolution.X), H: int32(resolution.Y)})
 
-	_, _, w, h, _ := s.texture.Query()
-	s.renderer.Copy(s.texture, nil, &sdl.Rect{X: int32Abs(int32(resolution.X)/2 - w/2), Y: int32Abs(int32(resolution.Y)/2 - h/2), W: w, H: h})
+	w, h, _ := s.image.Size()
+	s.image.Draw(s.renderer, &sdl.Rect{X: int32Abs(int32(resolution.X)/2 - w/2), Y: int32Abs(int32(resolution.Y)/2 - h/2), W: w, H: h}, s.opacity)
 
-	return s.opacity == 0, nil
+	return s.handle.Done(), nil
 }
 
-func (s *Splash) FadeIn() func(time.Duration) bool {
+// FadeIn returns a tween that shows the splash in a second
+func (s *Splash) FadeIn() tween.Tween {
 	s.opacity = 0
 
-	return func(dt time.Duration) bool {
-		if s.opacity < 1 {
-			s.opacity += dt.Seconds()
-		}
-
-		// It will ensure opacity not exceed from 1
-		if s.opacity > 1 {
-			s.opacity = 1
-		}
-
-		return s.opacity == 1
-	}
+	return tween.To(&s.opacity, 1, time.Second, ease.Linear)
 }
 
-func (s *Splash) FadeOut() func(time.Duration) bool {
-	s.opacity = 1
-
-	return func(dt time.Duration) bool {
-		if s.opacity > 0 {
-			s.opacity -= dt.Seconds()
-		}
-
-		// It will ensure opacity not exceed from 1
-		if s.opacity < 0 {
-			s.opacity = 0
-		}
-
-		return s.opacity == 0
-	}
+// FadeOut returns a tween that hides the splash in a second
+func (s *Splash) FadeOut() tween.Tween {
+	return tween.To(&s.opacity, 0, time.Second, ease.Linear)
 }
 
 func (s *Splash) Destroy() {
-	if s.texture != nil {
-		s.texture.Destroy()
+	s.handle.Cancel()
+
+	if s.image != nil {
+		s.image.Destroy()
 	}
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/moheb2000/fufu/internal/script"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Sprite is the image of a character that is shown over the background
+type Sprite struct {
+	spriteParams *SpriteParams
+	image        *Image
+	renderer     *sdl.Renderer
+	opacity      float64
+	// y moves the sprite down from the bottom of the scene. Scripts can change it with tween
+	y float64
+}
+
+type SpriteParams struct {
+	// Character is the name of the character. A character has only one sprite in the scene
+	Character  string
+	Expression string
+	Path       string
+	Show       script.Show
+	App        *Application
+}
+
+func newSprite(renderer *sdl.Renderer, p *SpriteParams) (*Sprite, error) {
+	s := Sprite{
+		spriteParams: p,
+		renderer:     renderer,
+		opacity:      1,
+	}
+
+	err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// load loads the image of the sprite path and frees the old one
+func (s *Sprite) load() error {
+	image, err := s.spriteParams.App.loadImage(s.renderer, s.spriteParams.Path)
+	if err != nil {
+		return err
+	}
+
+	if s.image != nil {
+		s.image.Destroy()
+	}
+	s.image = image
+
+	return nil
+}
+
+// setExpression changes the image of the sprite. The sprite stays the same if the image can't be loaded
+func (s *Sprite) setExpression(expression string, path string) error {
+	if s.spriteParams.Path == path {
+		s.spriteParams.Expression = expression
+		return nil
+	}
+
+	old := s.spriteParams.Path
+	s.spriteParams.Path = path
+	if err := s.load(); err != nil {
+		s.spriteParams.Path = old
+		return err
+	}
+	s.spriteParams.Expression = expression
+
+	return nil
+}
+
+// draw draws the sprite with the camera
+func (s *Sprite) draw(scene *sdl.Rect, camera *Camera) error {
+	r, err := s.rect(scene)
+	if err != nil {
+		return err
+	}
+
+	return s.image.Draw(s.renderer, camera.apply(float64(r.X), float64(r.Y), float64(r.W), float64(r.H), scene), s.opacity)
+}
+
+// rect returns the place of the sprite on the bottom of the scene rectangle without the camera. Sprites taller than the scene are scaled down to its height
+func (s *Sprite) rect(scene *sdl.Rect) (*sdl.Rect, error) {
+	w, h, err := s.image.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	if h > scene.H {
+		w = w * scene.H / h
+		h = scene.H
+	}
+
+	x := scene.X + int32(s.spriteParams.Show.At*float64(scene.W-w))
+	y := scene.Y + scene.H - h + int32(s.y)
+
+	return &sdl.Rect{X: x, Y: y, W: w, H: h}, nil
+}
+
+func (s *Sprite) Destroy() {
+	if s.image != nil {
+		s.image.Destroy()
+	}
+}
+
+// findSprite returns the sprite of the character in the scene or nil
+func (app *Application) findSprite(character string) *Sprite {
+	for _, s := range app.sprites {
+		if s.spriteParams.Character == character {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// removeSprite removes the sprite of the character from the scene
+func (app *Application) removeSprite(character string) {
+	app.sprites = slices.DeleteFunc(app.sprites, func(s *Sprite) bool {
+		if s.spriteParams.Character == character {
+			s.Destroy()
+			return true
+		}
+
+		return false
+	})
+}
+
+// clearSprites removes all sprites from the scene
+func (app *Application) clearSprites() {
+	for _, s := range app.sprites {
+		s.Destroy()
+	}
+	app.sprites = nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/moheb2000/fufu/internal/save"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Story records every step of the script, so the same position can be reached again by replaying the script from the beginning
+type Story struct {
+	// steps has one value for every resume of the coroutine. 0 means a normal resume and any other value is the option chosen by the player
+	steps []int
+	// replaying is true while the script runs headlessly to reach a saved step. Bindings shouldn't play sounds, splash screens or animations in this state
+	replaying bool
+	music     MusicState
+	// yield is the name of the binding that yielded the script last time
+	yield     string
+	snapshots []Snapshot
+	// lineRead is true if the player had read the current line before it was shown
+	lineRead bool
+	// lineLength is the number of characters in the current line
+	lineLength int
+	// inputs are the texts typed for the input calls. Steps can only keep numbers, so inputs are recorded here and used again by replaying
+	inputs []string
+	// inputStep is the number of inputs that the script has received
+	inputStep int
+}
+
+// MusicState keeps the last music requested by the script, so it can be played again after replaying
+type MusicState struct {
+	Path   string
+	Loop   bool
+	Paused bool
+}
+
+// resume continues the script and records the step. If the script is waiting for a choice, result is the chosen option. Errors of the script are shown in the error overlay
+func (app *Application) resume(result int) error {
+	if app.storyFinished() {
+		return nil
+	}
+
+	// Options and text input widgets must be removed after the player chooses one of them or types the text
+	if app.state == OPTIONS_STATE || app.state == INPUT_STATE {
+		app.dialogs.RemoveLastWidget()
+		app.state = NOVEL_STATE
+	}
+
+	app.story.steps = append(app.story.steps, result)
+
+	// Voice belongs to the old line and the auto mode timer starts again for the new line. Texts of the old line are shown completely, even if skip or auto mode resume the script while they are revealing
+	app.aum.StopVoice()
+	app.autoTimer = 0
+	app.finishReveal()
+
+	var args []lua.LValue
+	if result != 0 {
+		args = append(args, lua.LNumber(result))
+	}
+
+	// Input returns the next recorded text. A save without it gets an empty text
+	if app.story.yield == "input" {
+		value := ""
+		if app.story.inputStep < len(app.story.inputs) {
+			value = app.story.inputs[app.story.inputStep]
+		}
+		app.story.inputStep++
+		args = append(args, lua.LString(value))
+	}
+
+	state, err := app.lua.runner.Resume(args...)
+	if err != nil {
+		app.showError(err)
+		return err
+	}
+
+	if state == lua.ResumeYield {
+		app.takeSnapshot()
+	}
+
+	return nil
+}
+
+// storyFinished checks if the script can't be resumed anymore, because it's finished, failed or not loaded
+func (app *Application) storyFinished() bool {
+	return app.lua.runner == nil || app.lua.runner.Dead()
+}
+
+// yield records the name of the binding and yields the script
+func (app *Application) yield(L *lua.LState, name string) int {
+	app.story.yield = name
+
+	return L.Yield(lua.LNil)
+}
+
+// replay runs the script from the beginning with the provided steps and inputs without playing sounds and animations
+func (app *Application) replay(steps []int, inputs []string) error {
+	err := app.resetStory()
+	if err != nil {
+		app.showError(err)
+		return err
+	}
+
+	app.state = NOVEL_STATE
+	app.story.replaying = true
+	app.story.inputs = slices.Clone(inputs)
+	for _, step := range steps {
+		if err := app.resume(step); err != nil {
+			app.story.replaying = false
+			return err
+		}
+	}
+	app.story.replaying = false
+
+	// Waits don't start while replaying, so a replay that ends in a wait goes on when the replayed animations are finished
+	if app.story.waiting() {
+		app.waiting = nil
+		app.state = WAIT_STATE
+	}
+
+	// Inputs after the replayed steps belong to a position that the story has left
+	app.story.inputs = app.story.inputs[:min(app.story.inputStep, len(app.story.inputs))]
+
+	// Play the music that was playing at the saved step
+	music := app.story.music
+	if music.Path != "" {
+		app.aum.PlayMusic(music.Path, music.Loop)
+
+		if music.Paused {
+			app.aum.PauseMusic()
+		}
+	}
+
+	return nil
+}
+
+// resetStory removes everything the script created and starts a new lua VM
+func (app *Application) resetStory() error {
+	app.aum.StopMusic()
+	app.story = &Story{}
+	app.typing = nil
+	*app.result = 0
+
+	// Tweens of the old story must not change the new one
+	app.am.FastForward()
+	app.waiting = nil
+	app.dialogsX = 0
+	app.dialogsY = 0
+	app.camera = newCamera()
+
+	if app.background != nil {
+		app.background.Destroy()
+		app.background = nil
+	}
+	app.clearSprites()
+	app.clearParticles()
+	app.finishTransition()
+
+	if app.splash != nil {
+		app.splash.Destroy()
+		app.splash = nil
+	}
+
+	if menu, ok := app.widgets["menu"]; ok {
+		menu.Destroy()
+		delete(app.widgets, "menu")
+	}
+
+	app.dialogs.Clear()
+	app.hideError()
+
+	app.lua.l.Close()
+
+	return app.initScript()
+}
+
+// saveGame writes the steps of the story to the save slot
+func (app *Application) saveGame(slot string) error {
+	return save.Write(app.saveDir, slot, &save.Save{
+		GameVersion: app.cfg.GameVersion,
+		Time:        time.Now(),
+		Label:       app.lua.runner.Flow.Current,
+		Steps:       app.story.steps,
+		Inputs:      app.story.inputs,
+	})
+}
+
+// loadGame reads the save slot and replays the script to the saved step
+func (app *Application) loadGame(slot string) error {
+	s, err := save.Read(app.saveDir, slot)
+	if err != nil {
+		return err
+	}
+
+	if s.GameVersion != app.cfg.GameVersion {
+		log.Printf("[WARNING] Save is created with game version %s but the current version is %s\n", s.GameVersion, app.cfg.GameVersion)
+	}
+
+	return app.replay(s.Steps, s.Inputs)
+}
+
+// submitInput resumes the script with the text typed by the player
+func (app *Application) submitInput(value string) {
+	app.story.inputs = append(app.story.inputs, value)
+	app.resume(0)
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"image/color"
+	"log"
+	"strings"
+
+	"github.com/moheb2000/fufu/internal/ease"
+	"github.com/moheb2000/fufu/internal/script"
+	"github.com/moheb2000/fufu/internal/tween"
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// MASK_SOFTNESS is the part of the gray levels of a mask that are half shown during a mask transition. It makes the edges of the mask smooth
+const MASK_SOFTNESS = 0.1
+
+// BLENDMODE_MASK keeps the color of the destination and multiplies its alpha by the alpha of the source
+var BLENDMODE_MASK = sdl.ComposeCustomBlendMode(
+	sdl.BLENDFACTOR_ZERO, sdl.BLENDFACTOR_ONE,
+	sdl.BLENDOPERATION_ADD,
+	sdl.BLENDFACTOR_ZERO, sdl.BLENDFACTOR_SRC_ALPHA,
+	sdl.BLENDOPERATION_ADD,
+)
+
+// Transition draws the change from the old scene to the new one
+type Transition struct {
+	renderer         *sdl.Renderer
+	transitionParams *TransitionParams
+	easing           ease.Func
+	// progress is the eased progress of the transition from 0 to 1
+	progress float64
+	handle   *tween.Handle
+	// gray is the gray level of every pixel of the mask image from 0 to 1
+	gray        []float64
+	maskW       int32
+	maskH       int32
+	maskPixels  []uint32
+	maskTexture *sdl.Texture
+	// target is the old scene with the alpha of the mask
+	target *sdl.Texture
+	done   bool
+}
+
+type TransitionParams struct {
+	Transition *script.Transition
+	// Old is the scene before the change. The transition destroys it when it's finished
+	Old *sdl.Texture
+}
+
+func newTransition(renderer *sdl.Renderer, p *TransitionParams) (*Transition, error) {
+	t := Transition{
+		renderer:         renderer,
+		transitionParams: p,
+	}
+
+	easing, ok := ease.Lookup(p.Transition.Easing)
+	if !ok {
+		easing = ease.Linear
+	}
+	t.easing = easing
+
+	if p.Transition.Name == "mask" {
+		err := t.loadMask()
+		if err != nil {
+			t.Destroy()
+			return nil, err
+		}
+	}
+
+	return &t, nil
+}
+
+// loadMask reads the gray levels of the mask image and creates the textures of the mask transition
+func (t *Transition) loadMask() error {
+	surface, err := img.Load(t.transitionParams.Transition.Mask)
+	if err != nil {
+		return err
+	}
+	defer surface.Free()
+
+	t.maskW = surface.W
+	t.maskH = surface.H
+	t.gray = make([]float64, surface.W*surface.H)
+	for y := range int(surface.H) {
+		for x := range int(surface.W) {
+			g := color.GrayModel.Convert(surface.At(x, y)).(color.Gray)
+			t.gray[y*int(surface.W)+x] = float64(g.Y) / 255
+		}
+	}
+	t.maskPixels = make([]uint32, len(t.gray))
+
+	t.maskTexture, err = t.renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_STREAMING, t.maskW, t.maskH)
+	if err != nil {
+		return err
+	}
+	t.maskTexture.SetBlendMode(BLENDMODE_MASK)
+
+	_, _, w, h, err := t.transitionParams.Old.Query()
+	if err != nil {
+		return err
+	}
+
+	t.target, err = t.renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, w, h)
+	if err != nil {
+		return err
+	}
+	t.target.SetBlendMode(sdl.BLENDMODE_BLEND)
+
+	return nil
+}
+
+// Tween returns a tween that can be played in animation manager to run the transition
+func (t *Transition) Tween() tween.Tween {
+	return tween.New(t.transitionParams.Transition.Duration, t.easing, func(p float64) {
+		t.progress = p
+	})
+}
+
+// finished checks if the transition has shown the whole new scene
+func (t *Transition) finished() bool {
+	return t.done || (t.handle != nil && t.handle.Done())
+}
+
+// draw draws the old scene and the new one in the scene rectangle based on the progress of the transition
+func (t *Transition) draw(scene *sdl.Texture, dst *sdl.Rect) error {
+	old := t.transitionParams.Old
+	name := t.transitionParams.Transition.Name
+	p := t.progress
+	w, h := dst.W, dst.H
+
+	// The mask is drawn to its own target before anything is drawn in the scene rectangle
+	if name == "mask" {
+		err := t.updateMask()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Slides and pushes move the scenes out of the scene rectangle
+	t.renderer.SetClipRect(dst)
+	defer t.renderer.SetClipRect(nil)
+
+	switch {
+	case name == "dissolve":
+		t.renderer.Copy(scene, nil, dst)
+		old.SetBlendMode(sdl.BLENDMODE_BLEND)
+		old.SetAlphaMod(uint8((1 - p) * 255))
+		t.renderer.Copy(old, nil, dst)
+	case strings.HasPrefix(name, "wipe_"):
+		// The edge of the wipe moves in the direction of the transition and the old scene stays behind it
+		t.renderer.Copy(scene, nil, dst)
+		old.SetBlendMode(sdl.BLENDMODE_NONE)
+		ew, eh := int32(p*float64(w)), int32(p*float64(h))
+		var src sdl.Rect
+		switch name {
+		case "wipe_left":
+			src = sdl.Rect{X: 0, Y: 0, W: w - ew, H: h}
+		case "wipe_right":
+			src = sdl.Rect{X: ew, Y: 0, W: w - ew, H: h}
+		case "wipe_up":
+			src = sdl.Rect{X: 0, Y: 0, W: w, H: h - eh}
+		case "wipe_down":
+			src = sdl.Rect{X: 0, Y: eh, W: w, H: h - eh}
+		}
+		t.renderer.Copy(old, &src, &sdl.Rect{X: dst.X + src.X, Y: dst.Y + src.Y, W: src.W, H: src.H})
+	case strings.HasPrefix(name, "slide_"), strings.HasPrefix(name, "push_"):
+		// The new scene comes from the opposite side of the direction. Pushes move the old scene out with it
+		direction := name[strings.Index(name, "_")+1:]
+		var dx, dy int32
+		switch direction {
+		case "left":
+			dx = -w
+		case "right":
+			dx = w
+		case "up":
+			dy = -h
+		case "down":
+			dy = h
+		}
+		moveX, moveY := int32(p*float64(dx)), int32(p*float64(dy))
+
+		old.SetBlendMode(sdl.BLENDMODE_NONE)
+		if strings.HasPrefix(name, "push_") {
+			t.renderer.Copy(old, nil, &sdl.Rect{X: dst.X + moveX, Y: dst.Y + moveY, W: w, H: h})
+		} else {
+			t.renderer.Copy(old, nil, dst)
+		}
+		t.renderer.Copy(scene, nil, &sdl.Rect{X: dst.X - dx + moveX, Y: dst.Y - dy + moveY, W: w, H: h})
+	case name == "fade":
+		// The old scene fades to the color in the first half and the new scene fades from it in the second half
+		c, _ := hexToSDLColor(t.transitionParams.Transition.Color)
+		alpha := p * 2
+		if p < 0.5 {
+			old.SetBlendMode(sdl.BLENDMODE_NONE)
+			t.renderer.Copy(old, nil, dst)
+		} else {
+			t.renderer.Copy(scene, nil, dst)
+			alpha = (1 - p) * 2
+		}
+		t.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+		t.renderer.SetDrawColor(c.R, c.G, c.B, uint8(alpha*255))
+		t.renderer.FillRect(dst)
+	case name == "mask":
+		t.renderer.Copy(scene, nil, dst)
+		t.renderer.Copy(t.target, nil, dst)
+	default:
+		t.renderer.Copy(scene, nil, dst)
+	}
+
+	return nil
+}
+
+// updateMask draws the old scene to the target with the alpha of the mask. Pixels of the mask that are darker than the progress show the new scene
+func (t *Transition) updateMask() error {
+	threshold := t.progress*(1+MASK_SOFTNESS) - MASK_SOFTNESS
+	for i, g := range t.gray {
+		alpha := min(max((g-threshold)/MASK_SOFTNESS, 0), 1)
+		t.maskPixels[i] = 0xffffff00 | uint32(alpha*255)
+	}
+
+	err := t.maskTexture.UpdateRGBA(nil, t.maskPixels, int(t.maskW))
+	if err != nil {
+		return err
+	}
+
+	t.renderer.SetRenderTarget(t.target)
+	t.transitionParams.Old.SetBlendMode(sdl.BLENDMODE_NONE)
+	t.renderer.Copy(t.transitionParams.Old, nil, nil)
+	t.renderer.Copy(t.maskTexture, nil, nil)
+	t.renderer.SetRenderTarget(nil)
+
+	return nil
+}
+
+func (t *Transition) Destroy() {
+	t.done = true
+
+	if t.handle != nil {
+		t.handle.Cancel()
+	}
+
+	if t.transitionParams.Old != nil {
+		t.transitionParams.Old.Destroy()
+	}
+
+	if t.maskTexture != nil {
+		t.maskTexture.Destroy()
+	}
+
+	if t.target != nil {
+		t.target.Destroy()
+	}
+}
+
+// transit changes the scene with the change function and draws the change with the transition. Without a transition, or while the story is replaying or skipping, the scene changes at once
+func (app *Application) transit(transition *script.Transition, change func()) {
+	if transition == nil || transition.Duration == 0 || app.story.replaying || app.skipping() {
+		change()
+		return
+	}
+
+	// A new transition starts from the scene that the running transition is changing to
+	app.finishTransition()
+
+	old, err := app.snapshotScene()
+	if err != nil {
+		log.Println("[ERROR] Failed to start the transition:", err)
+		change()
+		return
+	}
+
+	change()
+
+	t, err := newTransition(app.renderer, &TransitionParams{
+		Transition: transition,
+		Old:        old,
+	})
+	if err != nil {
+		log.Println("[ERROR] Failed to start the transition:", err)
+		return
+	}
+
+	app.transition = t
+	t.handle = app.am.Play("transition", t.Tween())
+}
+
+// finishTransition stops the running transition, so the new scene is drawn completely
+func (app *Application) finishTransition() {
+	if app.transition != nil {
+		app.transition.Destroy()
+		app.transition = nil
+	}
+}
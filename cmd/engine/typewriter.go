@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/moheb2000/fufu/internal/gui"
+)
+
+// reveal shows the text of a line with the typewriter at the speed of the settings. If the speed is 0, the text fades in at once
+func (app *Application) reveal(t *gui.Text) {
+	if app.settings.TextSpeed <= 0 {
+		app.am.Play("", t.FadeIn())
+		return
+	}
+
+	app.am.Add(t.Typewriter(float64(app.settings.TextSpeed)))
+	app.typing = append(app.typing, t)
+}
+
+// advance shows the whole line if the typewriter is still revealing it, otherwise resumes the script
+func (app *Application) advance() {
+	revealing := false
+	for _, t := range app.typing {
+		if t.Revealing() {
+			t.Finish()
+			revealing = true
+		}
+	}
+
+	if revealing {
+		return
+	}
+
+	app.resume(0)
+}
+
+// finishReveal shows the whole texts of the current line before the script moves to the next one
+func (app *Application) finishReveal() {
+	for _, t := range app.typing {
+		t.Finish()
+	}
+
+	app.typing = nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/moheb2000/fufu/internal/script"
+	"github.com/moheb2000/fufu/internal/tween"
+)
+
+// updateWait resumes the script when its wait or the running animations are finished. Skip mode doesn't wait and jumps to the end of the animations
+func (app *Application) updateWait() {
+	if app.state != WAIT_STATE {
+		return
+	}
+
+	if app.skipping() {
+		app.am.FastForward()
+	} else if app.waiting != nil && !app.waiting.Done() {
+		return
+	} else if app.waiting == nil && app.am.Len() > 0 {
+		return
+	}
+
+	app.waiting = nil
+	app.state = NOVEL_STATE
+	app.resume(0)
+}
+
+// waiting checks if the script yielded in wait or wait_for_animations
+func (s *Story) waiting() bool {
+	return s.yield == "wait" || s.yield == "wait_for_animations"
+}
+
+// playTween plays a tween of the script. A new tween with the same name replaces the running one and the end values are set at once while replaying or skipping
+func (app *Application) playTween(name string, t tween.Tween) {
+	h := app.am.Play(name, t)
+	if app.story.replaying || app.skipping() {
+		h.FastForward()
+	}
+}
+
+// tweenValue returns the value of the property that the tween changes and the name of its tween in the animation manager. If the target isn't in the scene, the value is nil and the second return value is the problem
+func (app *Application) tweenValue(t *script.Tween, property string) (*float64, string) {
+	name := fmt.Sprintf("tween:%s:%s:%s", t.Target, t.Character, property)
+
+	switch t.Target {
+	case "bg":
+		if app.background == nil {
+			return nil, "there is no background to tween"
+		}
+
+		switch property {
+		case "x":
+			return &app.background.x, name
+		case "y":
+			return &app.background.y, name
+		case "alpha":
+			return &app.background.opacity, name
+		}
+	case "sprite":
+		sprite := app.findSprite(t.Character)
+		if sprite == nil {
+			return nil, fmt.Sprintf("character %q is not shown", t.Character)
+		}
+
+		switch property {
+		case "x":
+			return &sprite.spriteParams.Show.At, name
+		case "y":
+			return &sprite.y, name
+		case "alpha":
+			return &sprite.opacity, name
+		}
+	case "dialogs":
+		switch property {
+		case "x":
+			return &app.dialogsX, name
+		case "y":
+			return &app.dialogsY, name
+		}
+	}
+
+	return nil, fmt.Sprintf("%s can't be tweened on %s", property, t.Target)
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestStoryWaiting(t *testing.T) {
+	// A replay that ends in one of these yields must go back to the wait state
+	tests := map[string]bool{
+		"wait":                true,
+		"wait_for_animations": true,
+		"narrate":             false,
+		"choice":              false,
+		"":                    false,
+	}
+
+	for yield, expected := range tests {
+		s := Story{yield: yield}
+		if got := s.waiting(); got != expected {
+			t.Errorf("%q: expected %v; got: %v", yield, expected, got)
+		}
+	}
+}
@@ -10,16 +10,22 @@ function font(name, path) end
 ---@class character
 ---@field name string
 ---@field color string
+---@field sprites table<string, string>?
+
+---@class character_properties
+---@field sprites table<string, string>? The image paths of the character's expressions like {default = "alice.png", happy = "alice_happy.png"}. JSON files are animated images
 
 ---@param name string The character's name
 ---@param color string The color used to show the character's name
+---@param character_properties character_properties? A table containing properties of the character
 ---@return character character A table containing the caracter's data
-function character(name, color) end
+function character(name, color, character_properties) end
 
 ---@class properties
 ---@field font font?
 ---@field color string?
 ---@field font_size number?
+---@field expr string? The expression that the sprite of the character changes to (only for say)
 
 ---@param text string The text said by narrator
 ---@param properties properties? A table containing properties of the text
@@ -35,20 +41,124 @@ function say(character, text, properties) end
 ---@return result number The result of what user chose
 function choice(options, properties) end
 
+---@class transition
+---@field name string "dissolve", "wipe_left", "wipe_right", "wipe_up", "wipe_down", "slide_left", "slide_right", "slide_up", "slide_down", "push_left", "push_right", "push_up", "push_down", "fade" or "mask"
+---@field duration number? The duration in milliseconds. It's 500 by default
+---@field easing string? "linear", "ease_in", "ease_out", "ease_in_out" or a curve like "ease_out_back" (quad, cubic, back, elastic and bounce)
+---@field color string? The hex color that fade goes through
+---@field mask string? The path to the grayscale image of mask. Dark pixels show the new scene first
+
 ---@class bg_properties
----@field originx string?
----@field originy string?
+---@field originx "left"|"center"|"right"? The part of a wide background that is shown. It's "left" if it's nil
+---@field originy "top"|"center"|"bottom"? The part of a tall background that is shown. It's "top" if it's nil
 ---@field fade boolean?
+---@field transition transition|string? The transition from the old scene
 
----@param path string The path to the background image
+---@param path string The path to the background image or the JSON file of an animated image
 ---@param bg_properties bg_properties? A table containing properties of the background
 function bg(path, bg_properties) end
 
----@param path string The path to the image of splash screen
+---@class show_properties
+---@field at string|number? "left", "center", "right" or a number from 0 (left edge of the scene) to 1 (right edge)
+---@field z integer? Sprites with a bigger z are drawn over the others
+---@field transition transition|string? The transition from the old scene
+
+---@param character character The character that is shown
+---@param expression string? The expression of the sprite. It's "default" or the current expression of a shown character if it's nil
+---@param show_properties show_properties? A table containing properties of the sprite
+function show(character, expression, show_properties) end
+
+---@class hide_properties
+---@field transition transition|string? The transition from the old scene
+
+---@param character character The character that is hidden
+---@param hide_properties hide_properties? A table containing properties of hiding the sprite
+function hide(character, hide_properties) end
+
+---@param path string The path to the image of splash screen or the JSON file of an animated image
 ---@param color string The hex color of splash background
 ---@param duration integer The duration in milliseconds that splash screen will last
 function splash(path, color, duration) end
 
+---@param duration integer The duration in milliseconds that the script is paused
+function wait(duration) end
+
+---@class tween_properties
+---@field x number? The position of a character from 0 to 1 like the at property of show, or pixels that the background or dialogs are moved to the right
+---@field y number? Pixels that the target is moved down
+---@field alpha number? The opacity of a character or the background from 0 to 1
+
+---@param target character|"bg"|"dialogs" The character whose sprite is changed, the background or the dialogs
+---@param properties tween_properties The new values of the properties
+---@param duration integer The duration in milliseconds
+---@param easing string? The easing curve like "ease_out". It's "linear" if it's nil
+function tween(target, properties, duration, easing) end
+
+function wait_for_animations() end
+
+---@param intensity number The pixels that the scene is moved by at the start of the shake
+---@param duration integer The duration in milliseconds
+function shake(intensity, duration) end
+
+---@class zoom_properties
+---@field focus character|{x: number, y: number}? A shown character or a point of the scene from 0 to 1 that stays in its place. It's the center of the scene if it's nil
+---@field easing string? The easing curve like "ease_out". It's "linear" if it's nil
+
+---@param factor number The scale of the scene. 1 shows the whole scene
+---@param duration integer The duration in milliseconds
+---@param zoom_properties zoom_properties? A table containing properties of the zoom
+function zoom(factor, duration, zoom_properties) end
+
+---@class pan_properties
+---@field easing string? The easing curve like "ease_out". It's "linear" if it's nil
+
+---@param x number Pixels that the camera is moved to the right from its place
+---@param y number Pixels that the camera is moved down from its place
+---@param duration integer The duration in milliseconds
+---@param pan_properties pan_properties? A table containing properties of the pan
+function pan(x, y, duration, pan_properties) end
+
+---@class particles_area
+---@field x number? The left of the area from 0 to 1 of the scene
+---@field y number? The top of the area from 0 to 1 of the scene
+---@field w number? The width of the area from 0 to 1 of the scene
+---@field h number? The height of the area from 0 to 1 of the scene
+
+---@class particles_properties
+---@field name string? The name to replace or stop the emitter
+---@field shape "point"|"line"|"rect"? Where particles are spawned in the area. It's "rect" if it's nil
+---@field area particles_area? The area that particles are spawned in. It's the whole scene if it's nil
+---@field texture string? The path to the image of particles. Particles are rectangles of their color without it
+---@field color string? The hex color of particles
+---@field layer "back"|"front"? Draws particles under or over the sprites. It's "front" if it's nil
+---@field rate number? The number of particles spawned in a second
+---@field max integer? The number of particles that the emitter can have at once, from 0 to 10000
+---@field lifetime number|number[]? The life of particles in milliseconds or a range like {1000, 2000}
+---@field speed number|number[]? The speed of particles in pixels per second or a range
+---@field direction number|number[]? The direction of particles in degrees or a range. 0 is to the right and 90 is down
+---@field gravity number|number[]? The downward acceleration in pixels per second or {x, y}
+---@field rotation number|number[]? The first rotation of particles in degrees or a range
+---@field spin number|number[]? The rotation speed of particles in degrees per second or a range
+---@field size number|number[]? The width of particles in pixels or {start, end} in their life
+---@field alpha number|number[]? The opacity of particles from 0 to 1 or {start, end} in their life
+---@field stretch number? The height of particles to their width
+---@field align boolean? Rotates particles to their direction like rain drops
+---@field warm boolean? Fills the scene with particles from the start. Up to 15 seconds of particles are made at once
+
+---@param particles_properties particles_properties A table containing properties of the emitter
+function particles(particles_properties) end
+
+---@class weather_properties
+---@field intensity number? The number of particles from 0 to 1. It's 0.5 if it's nil
+---@field layer "back"|"front"? Draws the weather under or over the sprites. It's "front" if it's nil
+
+---@param name "rain"|"snow"|"petals"|"dust"|"clear" The weather. "clear" stops it
+---@param weather_properties weather_properties? A table containing properties of the weather
+function weather(name, weather_properties) end
+
+---@param name string The name of the emitter. Its particles die out at the end of their lifetime
+function stop_particles(name) end
+
 ---@param path string The path to music for playing
 ---@param loop boolean? whether the music should loop or not
 function play_music(path, loop) end
@@ -62,6 +172,64 @@ function resume_music() end
 ---@param path string the path to the sound for playing
 function play_sound(path) end
 
+---@param path string the path to the voice of the next line. The old voice stops when the story continues
+function play_voice(path) end
+
+---@param name string The label's name
+---@param fn function The part of the story that runs when the story jumps to the label or calls it
+function label(name, fn) end
+
+---Stops the current part of the story and continues from the label. A jump never returns, even inside pcall, xpcall or coroutines
+---@param name string The label's name
+function jump(name) end
+
+---Runs the label and continues from here when the label returns
+---@param name string The label's name
+---@param ... any Arguments passed to the label function
+---@return any ... The values returned by the label function
+function call(name, ...) end
+
+---Runs a module once and returns its result every time it's required. Module names are relative to the game directory and use dots as separators, so "chapters.one" loads "chapters/one.lua"
+---@param name string The module's name
+---@return any result The value returned by the module
+function require(name) end
+
+---Runs a script file every time it's included
+---@param path string The path to the file relative to the game directory
+---@param ... any Arguments passed to the file
+---@return any ... The values returned by the file
+function include(path, ...) end
+
+fufu = {}
+
+---File functions for game scripts. In sandbox mode io and os libraries are not available and these are the only way to work with files
+fufu.fs = {}
+
+---@param path string The path to a file inside the game directory
+---@return string? data The content of the file or nil if it can't be read
+---@return string? err The error message
+function fufu.fs.read(path) end
+
+---@param path string The path to a file inside the game directory
+---@return boolean exists Whether the file exists or not
+function fufu.fs.exists(path) end
+
+---@param path string? The path to a directory inside the game directory
+---@return string[]? names The names of the files in the directory
+---@return string? err The error message
+function fufu.fs.list(path) end
+
+---@param name string The name of a file inside the data directory of the game in the user data directory
+---@return string? data The content of the file or nil if it can't be read
+---@return string? err The error message
+function fufu.fs.read_save(name) end
+
+---@param name string The name of a file inside the data directory of the game in the user data directory
+---@param data string The content of the file
+---@return boolean? ok true if the file is written
+---@return string? err The error message
+function fufu.fs.write_save(name, data) end
+
 ---@return version string the engine version
 function get_engine_version() end
 
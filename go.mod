@@ -7,6 +7,7 @@ require github.com/veandco/go-sdl2 v0.4.40
 require (
 	github.com/gopxl/beep/v2 v2.1.1
 	github.com/yuin/gopher-lua v1.1.1
+	golang.org/x/text v0.14.0
 )
 
 require (
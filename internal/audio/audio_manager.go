@@ -17,6 +17,7 @@ import (
 type AudioManager struct {
 	sampleRate beep.SampleRate
 	music      *Music
+	voice      *Voice
 	sounds     map[string]*beep.Buffer
 }
 
@@ -26,6 +27,12 @@ type Music struct {
 	ctrl     *beep.Ctrl
 }
 
+// Voice is a clip that belongs to a line of dialog. Only one voice plays at a time
+type Voice struct {
+	ctrl *beep.Ctrl
+	done bool
+}
+
 func NewAudioManager(fps int) *AudioManager {
 	aum := &AudioManager{
 		sampleRate: beep.SampleRate(44100),
@@ -132,6 +139,60 @@ func (aum *AudioManager) PlaySound(path string) error {
 	return nil
 }
 
+// PlayVoice stops the old voice and plays the voice specified in the path parameter. Voices are not cached like sounds, because every line has its own voice
+func (aum *AudioManager) PlayVoice(path string) error {
+	aum.StopVoice()
+
+	streamer, format, err := decodeAudioFile(path)
+	if err != nil {
+		return err
+	}
+
+	v := Voice{
+		ctrl: &beep.Ctrl{Streamer: beep.Resample(4, format.SampleRate, aum.sampleRate, streamer)},
+	}
+	aum.voice = &v
+
+	// The callback runs after the voice is finished or stopped
+	speaker.Play(beep.Seq(v.ctrl, beep.Callback(func() {
+		v.done = true
+		streamer.Close()
+	})))
+
+	return nil
+}
+
+// StopVoice stops the voice if it is playing
+func (aum *AudioManager) StopVoice() {
+	if aum.voice != nil {
+		// Removing the streamer from ctrl makes it drained, so the callback of the voice will close it
+		speaker.Lock()
+		aum.voice.ctrl.Streamer = nil
+		aum.voice.done = true
+		speaker.Unlock()
+
+		aum.voice = nil
+	}
+}
+
+// IsVoicePlaying checks if a voice is playing right now
+func (aum *AudioManager) IsVoicePlaying() bool {
+	if aum.voice == nil {
+		return false
+	}
+
+	// done is changed by the speaker, so it must be read while the speaker is locked
+	speaker.Lock()
+	defer speaker.Unlock()
+
+	return !aum.voice.done
+}
+
+// ClearCache removes all loaded sounds from memory, so they will be read again from their files
+func (aum *AudioManager) ClearCache() {
+	aum.sounds = make(map[string]*beep.Buffer)
+}
+
 // decodeAudioFile gets a path file to music, decode it based on the format of the file and returns like other standard beep Decode functions
 func decodeAudioFile(path string) (beep.StreamSeekCloser, beep.Format, error) {
 	// Initialize variables
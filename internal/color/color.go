@@ -0,0 +1,37 @@
+// color package parses the hex colors used in config.json and game scripts
+package color
+
+import (
+	"fmt"
+	"image/color"
+	"regexp"
+	"strconv"
+)
+
+var hexColor = regexp.MustCompile(`^#?([0-9A-Fa-f]{8}|[0-9A-Fa-f]{6}|[0-9A-Fa-f]{3})$`)
+
+// ParseHex returns the color of a hex string like "#ff0000", its short form "#f00" or "#ff000080" with alpha. If hex is not valid, white is returned with an error
+func ParseHex(hex string) (color.RGBA, error) {
+	matches := hexColor.FindStringSubmatch(hex)
+
+	if matches == nil {
+		return color.RGBA{R: 255, G: 255, B: 255, A: 255}, fmt.Errorf("invalid hex color format: %s", hex)
+	}
+
+	digits := matches[1]
+	// Every digit of the short form is repeated, so "#f00" is "#ff0000"
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+
+	r, _ := strconv.ParseUint(digits[0:2], 16, 8)
+	g, _ := strconv.ParseUint(digits[2:4], 16, 8)
+	b, _ := strconv.ParseUint(digits[4:6], 16, 8)
+
+	a := uint64(255)
+	if len(digits) == 8 {
+		a, _ = strconv.ParseUint(digits[6:8], 16, 8)
+	}
+
+	return color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(a)}, nil
+}
@@ -0,0 +1,35 @@
+package color
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseHex(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	tests := []struct {
+		hex      string
+		expected color.RGBA
+		valid    bool
+	}{
+		{"#f80", color.RGBA{R: 255, G: 136, B: 0, A: 255}, true},
+		{"#FF8800", color.RGBA{R: 255, G: 136, B: 0, A: 255}, true},
+		{"ff8800", color.RGBA{R: 255, G: 136, B: 0, A: 255}, true},
+		{"#ff880080", color.RGBA{R: 255, G: 136, B: 0, A: 128}, true},
+		{"", white, false},
+		{"#ff", white, false},
+		{"#ff88", white, false},
+		{"#ff8800f", white, false},
+		{"#ff88000080", white, false},
+		{"#gg8800", white, false},
+		{"red", white, false},
+	}
+
+	for _, test := range tests {
+		got, err := ParseHex(test.hex)
+		if got != test.expected || (err == nil) != test.valid {
+			t.Errorf("%q: expected %v and valid %v; got: %v and %v", test.hex, test.expected, test.valid, got, err)
+		}
+	}
+}
@@ -12,12 +12,23 @@ type Config struct {
 	Title            string
 	FPS              int
 	GameVersion      string
+	Script           string
+	Sandbox          bool
+	DevMode          bool
 	FullScreen       bool
 	Resolution       int
 	BootScreen       bool
 	DefaultFont      string
 	DefaultTextColor string
-	DialogPanel      struct {
+	Language         string
+	SkipMode         string
+	TextSpeed        int
+	AutoMode         struct {
+		Enabled   bool
+		Delay     int
+		CharDelay int
+	}
+	DialogPanel struct {
 		Direction string
 		Color     string
 		Width     float64
@@ -47,11 +58,26 @@ func Get() (*Config, error) {
 		Title:            "Fufu Visual Novel Engine",
 		FPS:              30,
 		GameVersion:      "undefined",
+		Script:           "main.lua",
+		Sandbox:          true,
+		DevMode:          false,
 		FullScreen:       false,
 		Resolution:       1080,
 		BootScreen:       true,
 		DefaultFont:      "assets/UbuntuSans-Regular.ttf",
 		DefaultTextColor: "#ffffff",
+		Language:         "",
+		SkipMode:         "read",
+		TextSpeed:        40,
+		AutoMode: struct {
+			Enabled   bool
+			Delay     int
+			CharDelay int
+		}{
+			Enabled:   false,
+			Delay:     1000,
+			CharDelay: 30,
+		},
 		DialogPanel: struct {
 			Direction string
 			Color     string
@@ -90,5 +116,21 @@ func Get() (*Config, error) {
 		cfg.DialogPanel.Direction = "left"
 	}
 
+	if cfg.AutoMode.Delay < 0 || cfg.AutoMode.CharDelay < 0 {
+		log.Println("Auto mode delays can't be negative. Engine use the default delays as fallback")
+		cfg.AutoMode.Delay = 1000
+		cfg.AutoMode.CharDelay = 30
+	}
+
+	if cfg.TextSpeed < 0 {
+		log.Println("Text speed can't be negative. Engine use 40 characters per second as fallback speed")
+		cfg.TextSpeed = 40
+	}
+
+	if cfg.SkipMode != "read" && cfg.SkipMode != "all" {
+		log.Println("Skip mode is invalid. Engine use \"read\" as fallback skip mode")
+		cfg.SkipMode = "read"
+	}
+
 	return &cfg, nil
 }
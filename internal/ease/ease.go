@@ -0,0 +1,181 @@
+// ease package has the easing curves of animations. A curve changes the linear progress of an animation from 0 to 1 to the progress shown on the screen
+package ease
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Func is an easing curve. It returns 0 for 0 and 1 for 1
+type Func func(t float64) float64
+
+// CURVES are the easing curves that game scripts can use by their names
+var CURVES = map[string]Func{
+	"linear":              Linear,
+	"ease_in":             InQuad,
+	"ease_out":            OutQuad,
+	"ease_in_out":         InOutQuad,
+	"ease_in_quad":        InQuad,
+	"ease_out_quad":       OutQuad,
+	"ease_in_out_quad":    InOutQuad,
+	"ease_in_cubic":       InCubic,
+	"ease_out_cubic":      OutCubic,
+	"ease_in_out_cubic":   InOutCubic,
+	"ease_in_back":        InBack,
+	"ease_out_back":       OutBack,
+	"ease_in_out_back":    InOutBack,
+	"ease_in_elastic":     InElastic,
+	"ease_out_elastic":    OutElastic,
+	"ease_in_out_elastic": InOutElastic,
+	"ease_in_bounce":      InBounce,
+	"ease_out_bounce":     OutBounce,
+	"ease_in_out_bounce":  InOutBounce,
+}
+
+// These are the constants of the back and elastic curves. Back curves go past their start and end by BACK_OVERSHOOT
+const (
+	BACK_OVERSHOOT   = 1.70158
+	ELASTIC_PERIOD   = 2 * math.Pi / 3
+	ELASTIC_PERIOD_2 = 2 * math.Pi / 4.5
+)
+
+// Lookup returns the easing curve of the name. The second return value is false if there isn't a curve with this name
+func Lookup(name string) (Func, bool) {
+	f, ok := CURVES[name]
+
+	return f, ok
+}
+
+// Names returns the names of the easing curves in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(CURVES))
+	for name := range CURVES {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Progress returns the linear progress of an animation that has run for elapsed. It's 1 for animations without a duration
+func Progress(elapsed time.Duration, duration time.Duration) float64 {
+	if duration <= 0 || elapsed >= duration {
+		return 1
+	}
+
+	return max(elapsed.Seconds()/duration.Seconds(), 0)
+}
+
+func Linear(t float64) float64 {
+	return t
+}
+
+func InQuad(t float64) float64 {
+	return t * t
+}
+
+func OutQuad(t float64) float64 {
+	return t * (2 - t)
+}
+
+func InOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+
+	return -1 + (4-2*t)*t
+}
+
+func InCubic(t float64) float64 {
+	return t * t * t
+}
+
+func OutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+func InOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+func InBack(t float64) float64 {
+	return (BACK_OVERSHOOT+1)*t*t*t - BACK_OVERSHOOT*t*t
+}
+
+func OutBack(t float64) float64 {
+	return 1 + (BACK_OVERSHOOT+1)*math.Pow(t-1, 3) + BACK_OVERSHOOT*math.Pow(t-1, 2)
+}
+
+func InOutBack(t float64) float64 {
+	c := BACK_OVERSHOOT * 1.525
+	if t < 0.5 {
+		return math.Pow(2*t, 2) * ((c+1)*2*t - c) / 2
+	}
+
+	return (math.Pow(2*t-2, 2)*((c+1)*(t*2-2)+c) + 2) / 2
+}
+
+func InElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+
+	return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*ELASTIC_PERIOD)
+}
+
+func OutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*ELASTIC_PERIOD) + 1
+}
+
+func InOutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+
+	if t < 0.5 {
+		return -(math.Pow(2, 20*t-10) * math.Sin((20*t-11.125)*ELASTIC_PERIOD_2)) / 2
+	}
+
+	return math.Pow(2, -20*t+10)*math.Sin((20*t-11.125)*ELASTIC_PERIOD_2)/2 + 1
+}
+
+func InBounce(t float64) float64 {
+	return 1 - OutBounce(1-t)
+}
+
+// OutBounce falls to 1 and bounces three times with smaller bounces
+func OutBounce(t float64) float64 {
+	const n = 7.5625
+	const d = 2.75
+
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+func InOutBounce(t float64) float64 {
+	if t < 0.5 {
+		return (1 - OutBounce(1-2*t)) / 2
+	}
+
+	return (1 + OutBounce(2*t-1)) / 2
+}
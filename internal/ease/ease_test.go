@@ -0,0 +1,46 @@
+package ease
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCurves(t *testing.T) {
+	for name, f := range CURVES {
+		if math.Abs(f(0)) > 1e-9 || math.Abs(f(1)-1) > 1e-9 {
+			t.Errorf("%s: expected 0 at the start and 1 at the end; got: %v and %v", name, f(0), f(1))
+		}
+	}
+
+	if InBack(0.3) >= 0 || OutBack(0.7) <= 1 {
+		t.Errorf("back: expected to go past the start and the end; got: %v and %v", InBack(0.3), OutBack(0.7))
+	}
+
+	if math.Abs(OutBounce(1/2.75)-1) > 1e-9 || OutBounce(1.5/2.75) != 0.75 {
+		t.Errorf("bounce: expected 1 at the first fall and 0.75 at the top of the first bounce; got: %v and %v", OutBounce(1/2.75), OutBounce(1.5/2.75))
+	}
+
+	if InQuad(0.5) != 0.25 || OutQuad(0.5) != 0.75 || InOutQuad(0.25) != 0.125 {
+		t.Errorf("quad: expected 0.25, 0.75 and 0.125; got: %v, %v and %v", InQuad(0.5), OutQuad(0.5), InOutQuad(0.25))
+	}
+}
+
+func TestProgress(t *testing.T) {
+	tests := []struct {
+		elapsed  time.Duration
+		duration time.Duration
+		expected float64
+	}{
+		{0, time.Second, 0},
+		{250 * time.Millisecond, time.Second, 0.25},
+		{2 * time.Second, time.Second, 1},
+		{0, 0, 1},
+	}
+
+	for _, test := range tests {
+		if got := Progress(test.elapsed, test.duration); got != test.expected {
+			t.Errorf("progress of %v in %v: expected %v; got: %v", test.elapsed, test.duration, test.expected, got)
+		}
+	}
+}
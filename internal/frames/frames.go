@@ -0,0 +1,293 @@
+// frames package reads animated images. An animated image is a JSON file that describes its frames as parts of a spritesheet (an atlas or a grid) or as numbered image files, with the duration of every frame and its loop mode
+package frames
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// These are the loop modes of animations. LOOP starts again from the first frame, ONCE stops at the last frame and PING_PONG goes back and forth
+const (
+	LOOP      = "loop"
+	ONCE      = "once"
+	PING_PONG = "ping_pong"
+)
+
+// LOOP_MODES are the loop modes that animation files can use
+var LOOP_MODES = []string{LOOP, ONCE, PING_PONG}
+
+// DEFAULT_FRAME_DURATION is the duration of frames without a duration
+const DEFAULT_FRAME_DURATION = 100 * time.Millisecond
+
+// Animation is an animated image with its frames in order
+type Animation struct {
+	Frames []Frame
+	Loop   string
+}
+
+// Frame is an image of an animation
+type Frame struct {
+	// Path is the image of the frame. Frames of a spritesheet have the same path
+	Path string
+	// Rect is the part of the image that is the frame. It's nil for the whole image
+	Rect     *Rect
+	Duration time.Duration
+}
+
+type Rect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// File is the JSON file of an animated image. It must have one of frames, grid or sequence. Paths are relative to the directory of the file
+type File struct {
+	// Image is the spritesheet of atlas and grid animations. Meta.Image is read too, so atlases exported by tools like Aseprite can be used
+	Image string `json:"image"`
+	Meta  struct {
+		Image string `json:"image"`
+	} `json:"meta"`
+	// Frames are the parts of the spritesheet in an atlas
+	Frames []FileFrame `json:"frames"`
+	// Grid cuts the spritesheet to frames of the same size
+	Grid *Grid `json:"grid"`
+	// Sequence is the pattern of numbered images like "blink_%02d.png". Frames are counted from start until an image doesn't exist, if count is not set
+	Sequence string `json:"sequence"`
+	Start    *int   `json:"start"`
+	Count    int    `json:"count"`
+	// Duration is the duration of every frame in milliseconds and Durations has a duration for each frame instead
+	Duration  int    `json:"duration"`
+	Durations []int  `json:"durations"`
+	Loop      string `json:"loop"`
+}
+
+// FileFrame is a frame of an atlas. The rectangle can be in the frame field like the atlases of Aseprite
+type FileFrame struct {
+	Rect
+	Frame    *Rect `json:"frame"`
+	Duration int   `json:"duration"`
+}
+
+// Grid has the number of columns and rows of a spritesheet. Frames are read row by row and count can skip the empty cells at the end
+type Grid struct {
+	Columns int `json:"columns"`
+	Rows    int `json:"rows"`
+	Count   int `json:"count"`
+}
+
+// IsAnimation checks if the image path is an animation file
+func IsAnimation(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// Read reads the animation file
+func Read(path string) (*Animation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	a, err := f.animation(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return a, nil
+}
+
+// animation returns the frames of the file. dir is the directory of the file
+func (f *File) animation(dir string) (*Animation, error) {
+	a := Animation{Loop: f.Loop}
+	if a.Loop == "" {
+		a.Loop = LOOP
+	}
+
+	if !slices.Contains(LOOP_MODES, a.Loop) {
+		return nil, fmt.Errorf("loop must be one of %s; got %q", strings.Join(LOOP_MODES, ", "), a.Loop)
+	}
+
+	sheet := f.Image
+	if sheet == "" {
+		sheet = f.Meta.Image
+	}
+	if sheet != "" {
+		sheet = filepath.Join(dir, sheet)
+	}
+
+	kinds := 0
+	for _, set := range []bool{len(f.Frames) > 0, f.Grid != nil, f.Sequence != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return nil, errors.New("animation must have one of frames, grid or sequence")
+	}
+
+	var durations []int
+	var err error
+	switch {
+	case len(f.Frames) > 0:
+		if sheet == "" {
+			return nil, errors.New("frames of an atlas must have an image")
+		}
+
+		for _, ff := range f.Frames {
+			r := ff.Rect
+			if ff.Frame != nil {
+				r = *ff.Frame
+			}
+			if r.W <= 0 || r.H <= 0 {
+				return nil, fmt.Errorf("frame %d of the atlas must have a width and a height", len(a.Frames)+1)
+			}
+
+			a.Frames = append(a.Frames, Frame{Path: sheet, Rect: &r})
+			durations = append(durations, ff.Duration)
+		}
+	case f.Grid != nil:
+		if sheet == "" {
+			return nil, errors.New("grid must have an image")
+		}
+
+		a.Frames, err = f.Grid.frames(sheet)
+	default:
+		a.Frames, err = f.sequence(dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.Durations) > 0 {
+		if len(f.Durations) != len(a.Frames) {
+			return nil, fmt.Errorf("durations must have a duration for each of the %d frames; got %d", len(a.Frames), len(f.Durations))
+		}
+		durations = f.Durations
+	}
+
+	for i := range a.Frames {
+		d := f.Duration
+		if i < len(durations) && durations[i] != 0 {
+			d = durations[i]
+		}
+
+		if d < 0 {
+			return nil, fmt.Errorf("duration of frame %d must not be negative; got %d", i+1, d)
+		}
+
+		a.Frames[i].Duration = time.Duration(d) * time.Millisecond
+		if d == 0 {
+			a.Frames[i].Duration = DEFAULT_FRAME_DURATION
+		}
+	}
+
+	return &a, nil
+}
+
+// frames cuts the spritesheet to the cells of the grid
+func (g *Grid) frames(sheet string) ([]Frame, error) {
+	if g.Columns <= 0 || g.Rows <= 0 {
+		return nil, fmt.Errorf("grid must have columns and rows; got %d columns and %d rows", g.Columns, g.Rows)
+	}
+
+	count := g.Count
+	if count == 0 {
+		count = g.Columns * g.Rows
+	}
+	if count < 0 || count > g.Columns*g.Rows {
+		return nil, fmt.Errorf("count of grid must be from 1 to %d; got %d", g.Columns*g.Rows, count)
+	}
+
+	file, err := os.Open(sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", sheet, err)
+	}
+
+	w := config.Width / g.Columns
+	h := config.Height / g.Rows
+
+	frames := make([]Frame, 0, count)
+	for i := range count {
+		frames = append(frames, Frame{
+			Path: sheet,
+			Rect: &Rect{X: i % g.Columns * w, Y: i / g.Columns * h, W: w, H: h},
+		})
+	}
+
+	return frames, nil
+}
+
+// sequence returns the numbered images of the sequence. Without start, the first image can be 0 or 1
+func (f *File) sequence(dir string) ([]Frame, error) {
+	if f.Count < 0 {
+		return nil, fmt.Errorf("count of sequence must not be negative; got %d", f.Count)
+	}
+
+	path := func(n int) string {
+		return filepath.Join(dir, fmt.Sprintf(f.Sequence, n))
+	}
+
+	start := 0
+	if f.Start != nil {
+		start = *f.Start
+	} else if !exists(path(0)) {
+		start = 1
+	}
+
+	var frames []Frame
+	for n := start; f.Count == 0 || n < start+f.Count; n++ {
+		p := path(n)
+		if !exists(p) {
+			if f.Count > 0 {
+				return nil, fmt.Errorf("image %q of the sequence does not exist", fmt.Sprintf(f.Sequence, n))
+			}
+			break
+		}
+
+		frames = append(frames, Frame{Path: p})
+	}
+
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("sequence %q has no images", f.Sequence)
+	}
+
+	return frames, nil
+}
+
+// Paths returns the image files of the animation without repeating them
+func (a *Animation) Paths() []string {
+	var paths []string
+	for _, frame := range a.Frames {
+		if !slices.Contains(paths, frame.Path) {
+			paths = append(paths, frame.Path)
+		}
+	}
+
+	return paths
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,134 @@
+package frames
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFiles writes the files to a temporary directory. Files ending with .png are images of 64x32 pixels
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if !strings.HasSuffix(name, ".png") {
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 64, 32))); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	return dir
+}
+
+func TestRead(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"sheet.png":   "",
+		"blink_1.png": "",
+		"blink_2.png": "",
+		"grid.json":   `{"image": "sheet.png", "grid": {"columns": 2, "rows": 2, "count": 3}, "duration": 80}`,
+		"atlas.json":  `{"meta": {"image": "sheet.png"}, "frames": [{"frame": {"x": 0, "y": 0, "w": 20, "h": 32}, "duration": 2000}, {"x": 20, "y": 0, "w": 20, "h": 32}], "loop": "ping_pong"}`,
+		"blink.json":  `{"sequence": "blink_%d.png", "durations": [1500, 50], "loop": "once"}`,
+	})
+	sheet := filepath.Join(dir, "sheet.png")
+
+	tests := []struct {
+		file     string
+		expected *Animation
+	}{
+		{"grid.json", &Animation{Loop: LOOP, Frames: []Frame{
+			{Path: sheet, Rect: &Rect{X: 0, Y: 0, W: 32, H: 16}, Duration: 80 * time.Millisecond},
+			{Path: sheet, Rect: &Rect{X: 32, Y: 0, W: 32, H: 16}, Duration: 80 * time.Millisecond},
+			{Path: sheet, Rect: &Rect{X: 0, Y: 16, W: 32, H: 16}, Duration: 80 * time.Millisecond},
+		}}},
+		{"atlas.json", &Animation{Loop: PING_PONG, Frames: []Frame{
+			{Path: sheet, Rect: &Rect{X: 0, Y: 0, W: 20, H: 32}, Duration: 2 * time.Second},
+			{Path: sheet, Rect: &Rect{X: 20, Y: 0, W: 20, H: 32}, Duration: DEFAULT_FRAME_DURATION},
+		}}},
+		{"blink.json", &Animation{Loop: ONCE, Frames: []Frame{
+			{Path: filepath.Join(dir, "blink_1.png"), Duration: 1500 * time.Millisecond},
+			{Path: filepath.Join(dir, "blink_2.png"), Duration: 50 * time.Millisecond},
+		}}},
+	}
+
+	for _, test := range tests {
+		a, err := Read(filepath.Join(dir, test.file))
+		if err != nil {
+			t.Errorf("%s: expected no error; got: %v", test.file, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(a, test.expected) {
+			t.Errorf("%s: expected %+v; got: %+v", test.file, test.expected, a)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"sheet.png":  "",
+		"both.json":  `{"image": "sheet.png", "grid": {"columns": 2, "rows": 1}, "sequence": "f_%d.png"}`,
+		"loop.json":  `{"image": "sheet.png", "grid": {"columns": 2, "rows": 1}, "loop": "forever"}`,
+		"count.json": `{"sequence": "f_%d.png", "count": 2}`,
+		"times.json": `{"image": "sheet.png", "grid": {"columns": 2, "rows": 1}, "durations": [100]}`,
+	})
+
+	tests := map[string]string{
+		"both.json":  "animation must have one of frames, grid or sequence",
+		"loop.json":  `loop must be one of loop, once, ping_pong; got "forever"`,
+		"count.json": "of the sequence does not exist",
+		"times.json": "durations must have a duration for each of the 2 frames; got 1",
+	}
+
+	for file, expected := range tests {
+		_, err := Read(filepath.Join(dir, file))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected error %q; got: %v", file, expected, err)
+		}
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	frames := []Frame{{Duration: 100 * time.Millisecond}, {Duration: 200 * time.Millisecond}, {Duration: 100 * time.Millisecond}}
+
+	tests := []struct {
+		loop     string
+		indexes  []int
+		finished bool
+	}{
+		{LOOP, []int{0, 1, 1, 2, 0, 1}, false},
+		{PING_PONG, []int{0, 1, 1, 2, 1, 1}, false},
+		{ONCE, []int{0, 1, 1, 2, 2, 2}, true},
+	}
+
+	for _, test := range tests {
+		p := NewPlayer(&Animation{Loop: test.loop, Frames: frames})
+
+		var indexes []int
+		for range test.indexes {
+			indexes = append(indexes, p.Index())
+			p.Update(100 * time.Millisecond)
+		}
+
+		if !reflect.DeepEqual(indexes, test.indexes) || p.Done() != test.finished {
+			t.Errorf("%s: expected frames %v and finished %v; got: %v and %v", test.loop, test.indexes, test.finished, indexes, p.Done())
+		}
+	}
+}
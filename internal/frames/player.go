@@ -0,0 +1,69 @@
+package frames
+
+import "time"
+
+// Player chooses the frame of an animation over time
+type Player struct {
+	animation *Animation
+	frame     int
+	elapsed   time.Duration
+	// step is 1 while the frames go forward and -1 while a ping-pong animation goes back
+	step int
+	done bool
+}
+
+func NewPlayer(a *Animation) *Player {
+	return &Player{animation: a, step: 1}
+}
+
+// Update runs the animation for dt and returns true when an animation that doesn't loop is finished
+func (p *Player) Update(dt time.Duration) bool {
+	if p.done || len(p.animation.Frames) < 2 {
+		return p.done
+	}
+
+	p.elapsed += dt
+	for !p.done && p.elapsed >= p.animation.Frames[p.frame].Duration {
+		p.elapsed -= p.animation.Frames[p.frame].Duration
+		p.next()
+	}
+
+	return p.done
+}
+
+// Frame returns the frame that is shown now
+func (p *Player) Frame() Frame {
+	return p.animation.Frames[p.frame]
+}
+
+// Index returns the position of the frame that is shown now
+func (p *Player) Index() int {
+	return p.frame
+}
+
+// Done checks if an animation that doesn't loop is finished
+func (p *Player) Done() bool {
+	return p.done
+}
+
+// next goes to the next frame with the loop mode of the animation
+func (p *Player) next() {
+	n := len(p.animation.Frames)
+
+	switch p.animation.Loop {
+	case ONCE:
+		if p.frame == n-1 {
+			p.done = true
+			p.elapsed = 0
+			return
+		}
+		p.frame++
+	case PING_PONG:
+		if p.frame+p.step < 0 || p.frame+p.step >= n {
+			p.step = -p.step
+		}
+		p.frame += p.step
+	default:
+		p.frame = (p.frame + 1) % n
+	}
+}
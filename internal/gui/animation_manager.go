@@ -1,16 +1,26 @@
 // AnimationManager will control running animations in gui main loop. It will run animation functions that takes deltatime and returns true when aniamtion completed. It then remove completed animation from slice of animations so it will not run again.
 package gui
 
-import "time"
+import (
+	"slices"
+	"time"
+
+	"github.com/moheb2000/fufu/internal/tween"
+)
 
 type AnimationManager struct {
 	animations []func(dt time.Duration) bool
+	// handles are the running tweens that are played with a name
+	handles map[string]*tween.Handle
+	// ambient are the animations that don't change the story, like animated images. They run like other animations, but Len doesn't count them, so waiting for animations doesn't wait for images that loop forever
+	ambient []func(dt time.Duration) bool
 }
 
 // NewAnimationManager returns a new AnimationManager pointer with an empty slice as animation argument
 func NewAnimationManager() *AnimationManager {
 	return &AnimationManager{
 		animations: []func(dt time.Duration) bool{},
+		handles:    map[string]*tween.Handle{},
 	}
 }
 
@@ -19,16 +29,71 @@ func (am *AnimationManager) Add(animation func(time.Duration) bool) {
 	am.animations = append(am.animations, animation)
 }
 
+// AddAmbient appends an animation that Len doesn't count
+func (am *AnimationManager) AddAmbient(animation func(time.Duration) bool) {
+	am.ambient = append(am.ambient, animation)
+}
+
+// Len returns the number of running animations
+func (am *AnimationManager) Len() int {
+	return len(am.animations)
+}
+
+// Play runs the tween and returns its handle. If name is not empty, the running tween with the same name is cancelled and the new tween can be found with the name
+func (am *AnimationManager) Play(name string, t tween.Tween) *tween.Handle {
+	h := tween.NewHandle(name, t)
+
+	if name != "" {
+		if old, ok := am.handles[name]; ok {
+			old.Cancel()
+		}
+		am.handles[name] = h
+	}
+
+	am.Add(h.Animation())
+
+	return h
+}
+
+// Handle returns the running tween with the name or nil
+func (am *AnimationManager) Handle(name string) *tween.Handle {
+	h, ok := am.handles[name]
+	if !ok || h.Done() {
+		return nil
+	}
+
+	return h
+}
+
+// FastForward jumps to the end of all running tweens with a name
+func (am *AnimationManager) FastForward() {
+	for _, h := range am.handles {
+		h.FastForward()
+	}
+}
+
 // Update runs all animation functions and removes finished animations from animation slice
 func (am *AnimationManager) Update(dt time.Duration) {
-	// TODO: Check if using slices.Clip will improve performance and memory usage here or not
-	newAnimation := am.animations[:0]
+	// Animations can add new animations while they are running, for example in the completion functions of tweens, so they are added after the running animations
+	animations := am.animations
+	am.animations = []func(dt time.Duration) bool{}
 
-	for _, anim := range am.animations {
+	var running []func(dt time.Duration) bool
+	for _, anim := range animations {
 		if !anim(dt) {
-			newAnimation = append(newAnimation, anim)
+			running = append(running, anim)
 		}
 	}
 
-	am.animations = newAnimation
+	am.animations = append(running, am.animations...)
+
+	am.ambient = slices.DeleteFunc(am.ambient, func(anim func(time.Duration) bool) bool {
+		return anim(dt)
+	})
+
+	for name, h := range am.handles {
+		if h.Done() {
+			delete(am.handles, name)
+		}
+	}
 }
@@ -3,6 +3,8 @@ package gui
 import (
 	"testing"
 	"time"
+
+	"github.com/moheb2000/fufu/internal/tween"
 )
 
 func TestNewAnimationManager(t *testing.T) {
@@ -27,6 +29,23 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+func TestLen(t *testing.T) {
+	am := NewAnimationManager()
+
+	am.Add(func(dt time.Duration) bool { return false })
+	am.Add(func(dt time.Duration) bool { return true })
+
+	if am.Len() != 2 {
+		t.Errorf("Len should return the number of animations; expected: %v; got: %v", 2, am.Len())
+	}
+
+	am.Update(time.Second)
+
+	if am.Len() != 1 {
+		t.Errorf("Len should not count finished animations; expected: %v; got: %v", 1, am.Len())
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	am := NewAnimationManager()
 
@@ -48,3 +67,47 @@ func TestUpdate(t *testing.T) {
 		}
 	}
 }
+
+func TestPlay(t *testing.T) {
+	am := NewAnimationManager()
+
+	x := 0.0
+	first := am.Play("move", tween.To(&x, 1, time.Second, nil))
+	second := am.Play("move", tween.To(&x, 2, time.Second, nil))
+
+	if !first.Cancelled() {
+		t.Errorf("playing a tween with the same name should cancel the running tween")
+	}
+
+	if am.Handle("move") != second {
+		t.Errorf("Handle should return the last tween played with the name")
+	}
+
+	am.Update(time.Second)
+
+	if x != 2 || am.Handle("move") != nil || am.Len() != 0 {
+		t.Errorf("finished tween should be removed; expected x: %v; got: %v", 2, x)
+	}
+}
+
+func TestAddAmbient(t *testing.T) {
+	am := NewAnimationManager()
+
+	frames := 0
+	am.AddAmbient(func(time.Duration) bool {
+		frames++
+		return frames == 2
+	})
+
+	if am.Len() != 0 {
+		t.Errorf("ambient animations should not be counted; expected %v; got: %v", 0, am.Len())
+	}
+
+	am.Update(time.Second)
+	am.Update(time.Second)
+	am.Update(time.Second)
+
+	if frames != 2 {
+		t.Errorf("finished ambient animation should be removed; expected %v updates; got: %v", 2, frames)
+	}
+}
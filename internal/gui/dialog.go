@@ -69,11 +69,17 @@ func (d *Dialog) updateTexture() error {
 	d.renderer.SetDrawColor(0, 0, 0, 0)
 	d.renderer.Clear()
 
+	// Character is on the side that the text starts from
+	characterX, valueX := int32(0), ctdo.W
+	if d.dialogParams.Value.IsRTL() {
+		characterX, valueX = vtdo.W, 0
+	}
+
 	// Add character texture to the combined texture
-	d.renderer.Copy(ctdo.texture, nil, &sdl.Rect{X: 0, Y: 0, W: ctdo.W, H: ctdo.H})
+	d.renderer.Copy(ctdo.texture, nil, &sdl.Rect{X: characterX, Y: 0, W: ctdo.W, H: ctdo.H})
 
 	// Add value texture to the combined texture
-	d.renderer.Copy(vtdo.texture, nil, &sdl.Rect{X: ctdo.W, Y: 0, W: vtdo.W, H: vtdo.H})
+	d.renderer.Copy(vtdo.texture, nil, &sdl.Rect{X: valueX, Y: 0, W: vtdo.W, H: vtdo.H})
 
 	// Set render target back to nil
 	d.renderer.SetRenderTarget(nil)
@@ -1,16 +1,25 @@
 package gui
 
 import (
+	"fmt"
+
 	"github.com/veandco/go-sdl2/ttf"
 )
 
 type FontManager struct {
 	fonts map[string]map[int]*ttf.Font
+	paths map[string]string
+	// localeFonts are the default fonts of locales that don't use the default font of the game
+	localeFonts map[string]string
 }
 
 // NewFontManager returns a new FontManager pointer and make an empty map for fonts field
 func NewFontManager() *FontManager {
-	return &FontManager{fonts: make(map[string]map[int]*ttf.Font)}
+	return &FontManager{
+		fonts:       make(map[string]map[int]*ttf.Font),
+		paths:       make(map[string]string),
+		localeFonts: make(map[string]string),
+	}
 }
 
 // LoadFont gets font path and size, check if it is exists or not and if it's not, add it to fonts map as a cache storage
@@ -29,9 +38,73 @@ func (fm *FontManager) LoadFont(name string, path string, size int) (*ttf.Font,
 	}
 
 	fm.fonts[name][size] = font
+	fm.paths[name] = path
 	return font, nil
 }
 
+// AddFont records the path of a font, so it can be loaded later with only its name and a size. Markup tags of texts use fonts by their names
+func (fm *FontManager) AddFont(name string, path string) {
+	fm.paths[name] = path
+}
+
+// Font returns a font based on name and size. If the font isn't loaded in this size, it's loaded from the path of the font
+func (fm *FontManager) Font(name string, size int) (*ttf.Font, error) {
+	if font := fm.GetFont(name, size); font != nil {
+		return font, nil
+	}
+
+	path, exists := fm.paths[name]
+	if !exists {
+		return nil, fmt.Errorf("font %q is not loaded", name)
+	}
+
+	return fm.LoadFont(name, path, size)
+}
+
+// Lookup returns the name and size of a loaded font
+func (fm *FontManager) Lookup(font *ttf.Font) (string, int, bool) {
+	for name, sizes := range fm.fonts {
+		for size, f := range sizes {
+			if f == font {
+				return name, size, true
+			}
+		}
+	}
+
+	return "", 0, false
+}
+
+// SetLocaleFont sets the default font of a locale. Languages like Persian need a font that has their letters
+func (fm *FontManager) SetLocaleFont(locale string, path string) {
+	fm.localeFonts[locale] = path
+}
+
+// LoadDefaultFont loads the default font of the locale with the "default" name. If the locale doesn't have its own font, path is used
+func (fm *FontManager) LoadDefaultFont(locale string, path string, size int) (*ttf.Font, error) {
+	if localePath, exists := fm.localeFonts[locale]; exists {
+		path = localePath
+	}
+
+	return fm.LoadFont("default", path, size)
+}
+
+// Reload opens all cached fonts again from their files. Widgets that use the old fonts must be destroyed before calling Reload, because the old fonts are closed
+func (fm *FontManager) Reload() error {
+	for name, sizes := range fm.fonts {
+		for size, old := range sizes {
+			font, err := ttf.OpenFont(fm.paths[name], size)
+			if err != nil {
+				return err
+			}
+
+			old.Close()
+			sizes[size] = font
+		}
+	}
+
+	return nil
+}
+
 // GetFont returns a font based on name and size and if it doesn't exist, returns nil
 func (fm *FontManager) GetFont(name string, size int) *ttf.Font {
 	if sizes, exists := fm.fonts[name]; exists {
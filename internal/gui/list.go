@@ -148,6 +148,16 @@ func (l *List) RemoveLastWidget() {
 	l.MarkDirty()
 }
 
+// Clear removes and destroys all children of the list
+func (l *List) Clear() {
+	for _, widget := range l.listParams.Children {
+		widget.Destroy()
+	}
+
+	l.listParams.Children = []Widget{}
+	l.MarkDirty()
+}
+
 func (l *List) Destroy() {
 	if l.drawableObject.texture != nil {
 		l.drawableObject.texture.Destroy()
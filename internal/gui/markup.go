@@ -0,0 +1,192 @@
+package gui
+
+import (
+	"slices"
+
+	"github.com/moheb2000/fufu/internal/color"
+	"github.com/moheb2000/fufu/internal/markup"
+	"github.com/moheb2000/fufu/internal/rtl"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// renderedSpan is a part of a line rendered with its own style
+type renderedSpan struct {
+	surface *sdl.Surface
+	ascent  int32
+}
+
+// renderMarkup renders text with inline tags. Spans are wrapped together and every line is aligned to the baseline of its biggest font. While the typewriter shows the text, lines are cut after the visible glyphs, but the size of the text doesn't change
+func (t *Text) renderMarkup(spans []markup.Span) (*sdl.Surface, error) {
+	right := t.IsRTL()
+
+	// Arabic letters are measured with their shaped forms, because they have different widths
+	text := func(s markup.Span) string {
+		if right || rtl.HasRTL(s.Text) {
+			return rtl.Shape(s.Text)
+		}
+		return s.Text
+	}
+
+	measure := func(s markup.Span) int {
+		var w int
+		t.withStyle(s.Style, func(font *ttf.Font) {
+			w, _, _ = font.SizeUTF8(text(s))
+		})
+		return w
+	}
+
+	lines := markup.Lines(spans, t.textParams.limit, measure)
+
+	// Sizes of the lines are found before cutting them for the typewriter
+	ascents := make([]int32, len(lines))
+	heights := make([]int32, len(lines))
+	var width, height int32 = 1, 0
+	for i, line := range lines {
+		var w int32
+		ascents[i] = int32(t.textParams.Font.Ascent())
+		descent := int32(t.textParams.Font.LineSkip()) - ascents[i]
+		for _, s := range line {
+			w += int32(measure(s))
+			t.withStyle(s.Style, func(font *ttf.Font) {
+				ascents[i] = max(ascents[i], int32(font.Ascent()))
+				descent = max(descent, int32(font.LineSkip()-font.Ascent()))
+			})
+		}
+
+		heights[i] = ascents[i] + descent
+		width = max(width, w)
+		height += heights[i]
+	}
+
+	if t.visible >= 0 {
+		lines = markup.Truncate(lines, t.visible)
+	}
+
+	rendered := make([][]renderedSpan, len(lines))
+	defer func() {
+		for _, line := range rendered {
+			for _, r := range line {
+				r.surface.Free()
+			}
+		}
+	}()
+
+	widths := make([]int32, len(lines))
+	for i, line := range lines {
+		// Words of right-to-left lines are shown from right to left
+		if right {
+			line = slices.Clone(line)
+			slices.Reverse(line)
+		}
+
+		for _, s := range line {
+			value := text(s)
+			if right || rtl.HasRTL(s.Text) {
+				value = rtl.Visual(value, right)
+			}
+
+			var err error
+			t.withStyle(s.Style, func(font *ttf.Font) {
+				var surface *sdl.Surface
+				surface, err = font.RenderUTF8Blended(value, t.spanColor(s.Style))
+				if err != nil {
+					return
+				}
+
+				rendered[i] = append(rendered[i], renderedSpan{surface: surface, ascent: int32(font.Ascent())})
+				widths[i] += surface.W
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if right && t.textParams.limit > 0 {
+		width = max(width, int32(t.textParams.limit))
+	}
+
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, width, max(height, 1), 32, sdl.PIXELFORMAT_ARGB8888)
+	if err != nil {
+		return nil, err
+	}
+
+	var y int32
+	for i, line := range rendered {
+		var x int32
+		if right {
+			x = width - widths[i]
+		}
+
+		for _, r := range line {
+			// Alpha of the spans must be copied to the transparent surface instead of blending them
+			r.surface.SetBlendMode(sdl.BLENDMODE_NONE)
+			r.surface.Blit(nil, surface, &sdl.Rect{X: x, Y: y + ascents[i] - r.ascent, W: r.surface.W, H: r.surface.H})
+			x += r.surface.W
+		}
+
+		y += heights[i]
+	}
+
+	return surface, nil
+}
+
+// withStyle calls fn with the font of a style. Bold and italic styles are set on the font only while fn runs, because fonts are shared between widgets
+func (t *Text) withStyle(style markup.Style, fn func(*ttf.Font)) {
+	font := t.spanFont(style)
+
+	old := font.GetStyle()
+	s := old
+	if style.Bold {
+		s |= ttf.STYLE_BOLD
+	}
+	if style.Italic {
+		s |= ttf.STYLE_ITALIC
+	}
+
+	font.SetStyle(s)
+	defer font.SetStyle(old)
+
+	fn(font)
+}
+
+// spanFont returns the font of a style. Fonts and sizes of tags need the font manager, otherwise the font of the text is used
+func (t *Text) spanFont(style markup.Style) *ttf.Font {
+	font := t.textParams.Font
+	fonts := t.textParams.Fonts
+	if fonts == nil || (style.Font == "" && style.Size == 0) {
+		return font
+	}
+
+	name, size, ok := fonts.Lookup(font)
+	if !ok {
+		return font
+	}
+
+	if style.Font != "" {
+		name = style.Font
+	}
+
+	if style.Size > 0 {
+		size = style.Size
+	}
+
+	f, err := fonts.Font(name, size)
+	if err != nil {
+		return font
+	}
+
+	return f
+}
+
+// spanColor returns the color of a style
+func (t *Text) spanColor(style markup.Style) sdl.Color {
+	if style.Color == "" {
+		return t.textParams.Color
+	}
+
+	c, _ := color.ParseHex(style.Color)
+
+	return sdl.Color{R: c.R, G: c.G, B: c.B, A: c.A}
+}
@@ -1,7 +1,9 @@
 package gui
 
 import (
+	"math"
 	"strconv"
+	"time"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
@@ -13,11 +15,35 @@ type Options struct {
 	renderer       *sdl.Renderer
 	optionsParams  *OptionsParams
 	drawableObject *DrawableObject
+	// highlight is the index of the option highlighted with the arrow keys, or -1
+	highlight int
+	// colors are the colors of the option texts before highlighting them
+	colors []sdl.Color
+	// left is the time left to choose an option in a timed choice
+	left time.Duration
+}
+
+// Option is an option of the Options widget
+type Option struct {
+	Text *Text
+	// Value is the result of choosing the option
+	Value int
+	// Disabled options are shown, but they can't be chosen
+	Disabled bool
+	// Tooltip is shown under the options while the option is highlighted
+	Tooltip *Text
 }
 
 type OptionsParams struct {
 	Options *List
 	Result  *int
+	// Choices are the options in the order of their number keys. If it's empty, every child of Options is chosen with its own number
+	Choices        []Option
+	HighlightColor sdl.Color
+	// Timeout chooses Default when it ends. Timer must be added to the animation manager for it
+	Timeout   time.Duration
+	Default   int
+	Countdown *Text
 }
 
 func NewOptions(renderer *sdl.Renderer, p *OptionsParams) (*Options, error) {
@@ -27,10 +53,34 @@ func NewOptions(renderer *sdl.Renderer, p *OptionsParams) (*Options, error) {
 		dirty:          true,
 		done:           false,
 		drawableObject: &DrawableObject{},
+		highlight:      -1,
 	}
 
 	p.Options.makeParent(&o)
 
+	if len(p.Choices) == 0 {
+		for i, child := range p.Options.listParams.Children {
+			text, _ := child.(*Text)
+			p.Choices = append(p.Choices, Option{Text: text, Value: i + 1})
+		}
+	}
+
+	for _, choice := range p.Choices {
+		var color sdl.Color
+		if choice.Text != nil {
+			color = choice.Text.textParams.Color
+		}
+		o.colors = append(o.colors, color)
+
+		if choice.Tooltip != nil {
+			choice.Tooltip.makeParent(&o)
+		}
+	}
+
+	if p.Countdown != nil {
+		p.Countdown.makeParent(&o)
+	}
+
 	err := o.updateTexture()
 	if err != nil {
 		return nil, err
@@ -50,7 +100,53 @@ func (o *Options) updateTexture() error {
 		o.drawableObject.texture.Destroy()
 	}
 
-	o.drawableObject, _ = o.optionsParams.Options.Draw()
+	// The tooltip of the highlighted option and the countdown are shown under the options
+	children := []Widget{o.optionsParams.Options}
+	if tooltip := o.tooltip(); tooltip != nil {
+		children = append(children, tooltip)
+	}
+	if o.optionsParams.Countdown != nil && o.left > 0 {
+		children = append(children, o.optionsParams.Countdown)
+	}
+
+	spacing := o.optionsParams.Options.listParams.Spacing
+	var w, h int32
+	drawables := make([]*DrawableObject, len(children))
+	for i, child := range children {
+		do, err := child.Draw()
+		if err != nil {
+			return err
+		}
+		drawables[i] = do
+
+		w = max(w, do.W)
+		h += do.H + spacing
+	}
+	h -= spacing
+
+	texture, err := o.renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, max(w, 1), max(h, 1))
+	if err != nil {
+		return err
+	}
+
+	o.renderer.SetRenderTarget(texture)
+
+	// Making background transparent without lowering text quality
+	texture.SetBlendMode(BLENDMOD_ONE)
+	o.renderer.SetDrawColor(0, 0, 0, 0)
+	o.renderer.Clear()
+
+	var y int32
+	for _, do := range drawables {
+		o.renderer.Copy(do.texture, nil, &sdl.Rect{X: 0, Y: y, W: do.W, H: do.H})
+		y += do.H + spacing
+	}
+
+	o.renderer.SetRenderTarget(nil)
+
+	o.drawableObject.W = max(w, 1)
+	o.drawableObject.H = max(h, 1)
+	o.drawableObject.texture = texture
 
 	o.dirty = false
 
@@ -66,6 +162,7 @@ func (o *Options) Draw() (*DrawableObject, error) {
 	return o.drawableObject, nil
 }
 
+// HandleEvent chooses an option with its number key. Arrow keys highlight the options and show their tooltips and enter chooses the highlighted option
 func (o *Options) HandleEvent(event sdl.Event) {
 	// Handle user input here
 	if o.done {
@@ -75,14 +172,22 @@ func (o *Options) HandleEvent(event sdl.Event) {
 	switch e := event.(type) {
 	case *sdl.KeyboardEvent:
 		if e.Type == sdl.KEYUP {
-			for i := range o.optionsParams.Options.listParams.Children {
-				if e.Keysym.Sym == sdl.GetKeyFromName(strconv.Itoa(i+1)) {
-					if *o.optionsParams.Result == 0 {
-						*o.optionsParams.Result = i + 1
-					}
-					o.done = true
+			switch e.Keysym.Sym {
+			case sdl.K_UP:
+				o.move(-1)
+			case sdl.K_DOWN:
+				o.move(1)
+			case sdl.K_RETURN, sdl.K_KP_ENTER:
+				if o.highlight >= 0 {
+					o.choose(o.highlight)
+				}
+			default:
+				for i := range o.optionsParams.Choices {
+					if e.Keysym.Sym == sdl.GetKeyFromName(strconv.Itoa(i+1)) {
+						o.choose(i)
 
-					break
+						break
+					}
 				}
 			}
 		}
@@ -91,6 +196,108 @@ func (o *Options) HandleEvent(event sdl.Event) {
 	o.optionsParams.Options.HandleEvent(event)
 }
 
+// Timer returns a func(time.Duration) bool that can be added to animation manager to count down the time of a timed choice. When the time ends, the default option is chosen
+func (o *Options) Timer() func(time.Duration) bool {
+	o.left = o.optionsParams.Timeout
+	o.updateCountdown()
+
+	return func(dt time.Duration) bool {
+		if o.done {
+			return true
+		}
+
+		o.left -= dt
+		if o.left <= 0 {
+			if *o.optionsParams.Result == 0 {
+				*o.optionsParams.Result = o.optionsParams.Default
+			}
+			o.done = true
+
+			return true
+		}
+
+		o.updateCountdown()
+
+		return false
+	}
+}
+
+// updateCountdown shows the seconds left in the countdown text
+func (o *Options) updateCountdown() {
+	countdown := o.optionsParams.Countdown
+	if countdown == nil {
+		return
+	}
+
+	value := strconv.Itoa(int(math.Ceil(o.left.Seconds())))
+	if countdown.textParams.Value != value {
+		countdown.SetValue(value)
+	}
+}
+
+// choose sets the result to the value of the option i. Disabled options can't be chosen
+func (o *Options) choose(i int) {
+	choice := o.optionsParams.Choices[i]
+	if choice.Disabled {
+		return
+	}
+
+	if *o.optionsParams.Result == 0 {
+		*o.optionsParams.Result = choice.Value
+	}
+	o.done = true
+}
+
+// move highlights the option that is step options after the highlighted option on the screen
+func (o *Options) move(step int) {
+	// Options are found in the order of the list, because it may show them in another order than their numbers
+	var order []int
+	for _, child := range o.optionsParams.Options.listParams.Children {
+		for i, choice := range o.optionsParams.Choices {
+			if choice.Text != nil && Widget(choice.Text) == child {
+				order = append(order, i)
+			}
+		}
+	}
+
+	if len(order) == 0 {
+		return
+	}
+
+	position := -1
+	for p, i := range order {
+		if i == o.highlight {
+			position = p
+		}
+	}
+
+	switch {
+	case position < 0 && step < 0:
+		position = len(order) - 1
+	case position < 0:
+		position = 0
+	default:
+		position = (position + step + len(order)) % len(order)
+	}
+
+	if o.highlight >= 0 {
+		o.optionsParams.Choices[o.highlight].Text.setColor(o.colors[o.highlight])
+	}
+
+	o.highlight = order[position]
+	o.optionsParams.Choices[o.highlight].Text.setColor(o.optionsParams.HighlightColor)
+	o.MarkDirty()
+}
+
+// tooltip returns the tooltip of the highlighted option or nil
+func (o *Options) tooltip() *Text {
+	if o.highlight < 0 {
+		return nil
+	}
+
+	return o.optionsParams.Choices[o.highlight].Tooltip
+}
+
 func (o *Options) makeParent(parent Widget) {
 	o.parent = parent
 }
@@ -101,6 +308,16 @@ func (o *Options) getParent() Widget {
 
 func (o *Options) setLimit(limit int) {
 	o.optionsParams.Options.setLimit(limit)
+
+	for _, choice := range o.optionsParams.Choices {
+		if choice.Tooltip != nil {
+			choice.Tooltip.setLimit(limit)
+		}
+	}
+
+	if o.optionsParams.Countdown != nil {
+		o.optionsParams.Countdown.setLimit(limit)
+	}
 }
 
 func (o *Options) MarkDirty() {
@@ -111,11 +328,24 @@ func (o *Options) MarkDirty() {
 	}
 }
 
+// Destroy frees the options and stops the timer of a timed choice
 func (o *Options) Destroy() {
+	o.done = true
+
 	// Destroy drawable object
 	if o.drawableObject.texture != nil {
 		o.drawableObject.texture.Destroy()
 	}
 
 	o.optionsParams.Options.Destroy()
+
+	for _, choice := range o.optionsParams.Choices {
+		if choice.Tooltip != nil {
+			choice.Tooltip.Destroy()
+		}
+	}
+
+	if o.optionsParams.Countdown != nil {
+		o.optionsParams.Countdown.Destroy()
+	}
 }
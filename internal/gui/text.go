@@ -3,6 +3,10 @@ package gui
 import (
 	"time"
 
+	"github.com/moheb2000/fufu/internal/ease"
+	"github.com/moheb2000/fufu/internal/markup"
+	"github.com/moheb2000/fufu/internal/rtl"
+	"github.com/moheb2000/fufu/internal/tween"
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
 )
@@ -14,12 +18,18 @@ type Text struct {
 	textParams     *TextParams
 	drawableObject *DrawableObject
 	opacity        float64
+	// visible is the number of glyphs shown by the typewriter reveal. It's -1 if the text doesn't use the typewriter
+	visible int
+	// pause is the time left from the {w} tag that stopped the typewriter
+	pause time.Duration
 }
 
 type TextParams struct {
 	Value string
 	Color sdl.Color
 	Font  *ttf.Font
+	// Fonts is used by the {font} and {size} tags of the value. Without it, these tags are ignored
+	Fonts *FontManager
 	limit int
 }
 
@@ -32,6 +42,7 @@ func NewText(renderer *sdl.Renderer, p *TextParams) (*Text, error) {
 		dirty:          true,
 		drawableObject: &DrawableObject{},
 		opacity:        1,
+		visible:        -1,
 	}
 
 	err := t.updateTexture()
@@ -54,25 +65,75 @@ func (t *Text) Draw() (*DrawableObject, error) {
 
 func (t *Text) HandleEvent(event sdl.Event) {}
 
-// FadeIn returns a func(time.Duration) bool that can be added to animation manager that will run independent from draw loop
-func (t *Text) FadeIn() func(time.Duration) bool {
+// FadeIn returns a tween that can be played in animation manager to show the text in a second
+func (t *Text) FadeIn() tween.Tween {
 	t.opacity = 0
 
+	return tween.New(time.Second, ease.Linear, func(p float64) {
+		t.opacity = p
+		t.MarkDirty()
+	})
+}
+
+// Typewriter returns a func(time.Duration) bool that can be added to animation manager to show the text glyph by glyph. cps is the number of glyphs shown in a second and {w} tags stop it for their seconds
+func (t *Text) Typewriter(cps float64) func(time.Duration) bool {
+	value := t.textParams.Value
+	total := markup.Glyphs(markup.Plain(value))
+	pauses := markup.Pauses(value)
+	progress := 0.0
+	t.visible = 0
+	t.MarkDirty()
+
 	return func(dt time.Duration) bool {
-		if t.opacity < 1 {
-			t.opacity += dt.Seconds()
-			t.MarkDirty()
+		if t.pause > 0 {
+			t.pause -= dt
+			return false
+		}
+
+		if t.visible >= total {
+			return true
 		}
 
-		// It will ensure opacity not exceed from 1
-		if t.opacity > 1 {
-			t.opacity = 1
+		progress += dt.Seconds() * cps
+		shown := min(int(progress), total)
+
+		// Pauses that Finish has passed are removed before stopping at the next one
+		for len(pauses) > 0 && pauses[0].At < t.visible {
+			pauses = pauses[1:]
+		}
+
+		if len(pauses) > 0 && pauses[0].At <= shown {
+			shown = pauses[0].At
+			progress = float64(shown)
+			t.pause = pauses[0].Duration
+			pauses = pauses[1:]
+		}
+
+		if shown != t.visible {
+			t.visible = shown
+			t.MarkDirty()
 		}
 
-		return t.opacity == 1
+		return t.visible >= total && t.pause <= 0
 	}
 }
 
+// Revealing checks if the typewriter is still showing the text
+func (t *Text) Revealing() bool {
+	return t.visible >= 0 && (t.visible < markup.Glyphs(markup.Plain(t.textParams.Value)) || t.pause > 0)
+}
+
+// Finish shows the whole text if the typewriter is still showing it
+func (t *Text) Finish() {
+	if !t.Revealing() {
+		return
+	}
+
+	t.visible = markup.Glyphs(markup.Plain(t.textParams.Value))
+	t.pause = 0
+	t.MarkDirty()
+}
+
 // updateTexture updates the texture of the widget in every frame if dirty is true
 func (t *Text) updateTexture() error {
 	// Check if texture needs to update
@@ -85,8 +146,19 @@ func (t *Text) updateTexture() error {
 		t.drawableObject.texture.Destroy()
 	}
 
-	// Make a text texture with font and specified color
-	surface, err := t.textParams.Font.RenderUTF8BlendedWrapped(t.textParams.Value, t.textParams.Color, t.textParams.limit)
+	// Make a text texture with font and specified color. Styled text, text of the typewriter and right-to-left text need their own layout, because SDL_ttf renders a single style from left to right
+	spans := markup.Parse(t.textParams.Value)
+	value := markup.Plain(t.textParams.Value)
+	var surface *sdl.Surface
+	var err error
+	switch {
+	case markup.IsStyled(spans) || t.visible >= 0:
+		surface, err = t.renderMarkup(spans)
+	case rtl.HasRTL(value):
+		surface, err = t.renderLayout(value)
+	default:
+		surface, err = t.textParams.Font.RenderUTF8BlendedWrapped(value, t.textParams.Color, t.textParams.limit)
+	}
 	if err != nil {
 		return err
 	}
@@ -111,6 +183,79 @@ func (t *Text) updateTexture() error {
 	return nil
 }
 
+// renderLayout renders the lines of bidirectional text one by one. Lines of right-to-left text are aligned to the right of the width limit
+func (t *Text) renderLayout(value string) (*sdl.Surface, error) {
+	font := t.textParams.Font
+	lines := rtl.Layout(value, t.textParams.limit, func(s string) int {
+		w, _, _ := font.SizeUTF8(s)
+		return w
+	})
+
+	surfaces := make([]*sdl.Surface, len(lines))
+	defer func() {
+		for _, s := range surfaces {
+			if s != nil {
+				s.Free()
+			}
+		}
+	}()
+
+	var width int32 = 1
+	for i, line := range lines {
+		// SDL_ttf can't render empty lines, but they still take their height
+		if line == "" {
+			continue
+		}
+
+		s, err := font.RenderUTF8Blended(line, t.textParams.Color)
+		if err != nil {
+			return nil, err
+		}
+		surfaces[i] = s
+
+		width = max(width, s.W)
+	}
+
+	right := t.IsRTL()
+	if right && t.textParams.limit > 0 {
+		width = max(width, int32(t.textParams.limit))
+	}
+
+	lineSkip := int32(font.LineSkip())
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, width, max(lineSkip*int32(len(lines)), 1), 32, sdl.PIXELFORMAT_ARGB8888)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, s := range surfaces {
+		if s == nil {
+			continue
+		}
+
+		var x int32
+		if right {
+			x = width - s.W
+		}
+
+		// Alpha of the lines must be copied to the transparent surface instead of blending them
+		s.SetBlendMode(sdl.BLENDMODE_NONE)
+		s.Blit(nil, surface, &sdl.Rect{X: x, Y: lineSkip * int32(i), W: s.W, H: s.H})
+	}
+
+	return surface, nil
+}
+
+// IsRTL checks if the text is written from right to left
+func (t *Text) IsRTL() bool {
+	return rtl.IsRTL(markup.Plain(t.textParams.Value))
+}
+
+// SetValue changes the text and marks the widget dirty
+func (t *Text) SetValue(value string) {
+	t.textParams.Value = value
+	t.MarkDirty()
+}
+
 func (t *Text) setColor(color sdl.Color) {
 	t.textParams.Color = color
 	t.MarkDirty()
@@ -0,0 +1,299 @@
+package gui
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// CARET_BLINK is the time that the caret of a text input is visible or hidden
+const CARET_BLINK = 500 * time.Millisecond
+
+type TextInput struct {
+	parent          Widget
+	dirty           bool
+	done            bool
+	renderer        *sdl.Renderer
+	textInputParams *TextInputParams
+	drawableObject  *DrawableObject
+	value           []rune
+	// caret is the position of the caret in value
+	caret int
+	// composition is the text that an input method is composing. It isn't a part of value until the input method commits it
+	composition string
+	caretHidden bool
+	onSubmit    func(string)
+}
+
+type TextInputParams struct {
+	Prompt  *Text
+	Color   sdl.Color
+	Font    *ttf.Font
+	Default string
+	// MaxLength is the maximum number of characters. 0 means no limit
+	MaxLength int
+	// Allowed checks if a character can be typed. If it's nil, every character is allowed
+	Allowed func(string) bool
+}
+
+// NewTextInput returns a text input widget and starts SDL text input, so input methods can compose text for it
+func NewTextInput(renderer *sdl.Renderer, p *TextInputParams) (*TextInput, error) {
+	ti := TextInput{
+		renderer:        renderer,
+		textInputParams: p,
+		dirty:           true,
+		drawableObject:  &DrawableObject{},
+		value:           []rune(p.Default),
+	}
+	ti.caret = len(ti.value)
+
+	p.Prompt.makeParent(&ti)
+	sdl.StartTextInput()
+
+	err := ti.updateTexture()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ti, nil
+}
+
+// updateTexture draws the prompt and the value under it with the caret
+func (ti *TextInput) updateTexture() error {
+	if !ti.dirty {
+		return nil
+	}
+
+	if ti.drawableObject.texture != nil {
+		ti.drawableObject.texture.Destroy()
+	}
+
+	pdo, err := ti.textInputParams.Prompt.Draw()
+	if err != nil {
+		return err
+	}
+
+	font := ti.textInputParams.Font
+	before := string(ti.value[:ti.caret])
+	after := string(ti.value[ti.caret:])
+	beforeW, _, _ := font.SizeUTF8(before)
+	compositionW, _, _ := font.SizeUTF8(ti.composition)
+
+	var field *sdl.Texture
+	var fieldW int32
+	if value := before + ti.composition + after; value != "" {
+		surface, err := font.RenderUTF8Blended(value, ti.textInputParams.Color)
+		if err != nil {
+			return err
+		}
+		defer surface.Free()
+
+		field, err = ti.renderer.CreateTextureFromSurface(surface)
+		if err != nil {
+			return err
+		}
+		defer field.Destroy()
+
+		fieldW = surface.W
+	}
+
+	// The caret has its own width after the last character
+	fieldH := int32(font.Height())
+	w := max(pdo.W, fieldW+2, 1)
+	h := pdo.H + fieldH
+
+	texture, err := ti.renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, w, h)
+	if err != nil {
+		return err
+	}
+
+	ti.renderer.SetRenderTarget(texture)
+
+	// Making background transparent without lowering text quality
+	texture.SetBlendMode(BLENDMOD_ONE)
+	ti.renderer.SetDrawColor(0, 0, 0, 0)
+	ti.renderer.Clear()
+
+	ti.renderer.Copy(pdo.texture, nil, &sdl.Rect{X: 0, Y: 0, W: pdo.W, H: pdo.H})
+
+	c := ti.textInputParams.Color
+	ti.renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+	if field != nil {
+		ti.renderer.Copy(field, nil, &sdl.Rect{X: 0, Y: pdo.H, W: fieldW, H: fieldH})
+	}
+
+	// Composed text is underlined, so the player can see that it isn't committed yet
+	if ti.composition != "" {
+		ti.renderer.FillRect(&sdl.Rect{X: int32(beforeW), Y: h - 2, W: int32(compositionW), H: 1})
+	}
+
+	if !ti.caretHidden {
+		ti.renderer.FillRect(&sdl.Rect{X: int32(beforeW + compositionW), Y: pdo.H, W: 2, H: fieldH})
+	}
+
+	ti.renderer.SetRenderTarget(nil)
+
+	ti.drawableObject.W = w
+	ti.drawableObject.H = h
+	ti.drawableObject.texture = texture
+
+	ti.dirty = false
+
+	return nil
+}
+
+func (ti *TextInput) Draw() (*DrawableObject, error) {
+	err := ti.updateTexture()
+	if err != nil {
+		return nil, err
+	}
+
+	return ti.drawableObject, nil
+}
+
+// HandleEvent adds the text of text input events and handles the editing keys. Enter submits the value
+func (ti *TextInput) HandleEvent(event sdl.Event) {
+	if ti.done {
+		return
+	}
+
+	switch e := event.(type) {
+	case *sdl.TextInputEvent:
+		ti.composition = ""
+		ti.insert(e.GetText())
+	case *sdl.TextEditingEvent:
+		ti.composition = e.GetText()
+		ti.showCaret()
+	case *sdl.KeyboardEvent:
+		// Keys belong to the input method while it's composing
+		if e.Type != sdl.KEYDOWN || ti.composition != "" {
+			return
+		}
+
+		switch e.Keysym.Sym {
+		case sdl.K_BACKSPACE:
+			if ti.caret > 0 {
+				ti.value = append(ti.value[:ti.caret-1], ti.value[ti.caret:]...)
+				ti.caret--
+			}
+		case sdl.K_DELETE:
+			if ti.caret < len(ti.value) {
+				ti.value = append(ti.value[:ti.caret], ti.value[ti.caret+1:]...)
+			}
+		case sdl.K_LEFT:
+			ti.caret = max(ti.caret-1, 0)
+		case sdl.K_RIGHT:
+			ti.caret = min(ti.caret+1, len(ti.value))
+		case sdl.K_HOME:
+			ti.caret = 0
+		case sdl.K_END:
+			ti.caret = len(ti.value)
+		case sdl.K_v:
+			if e.Keysym.Mod&sdl.KMOD_CTRL != 0 {
+				if text, err := sdl.GetClipboardText(); err == nil {
+					ti.insert(text)
+				}
+			}
+		case sdl.K_RETURN, sdl.K_KP_ENTER:
+			ti.submit()
+			return
+		}
+
+		ti.showCaret()
+	}
+}
+
+// insert adds the allowed characters of text at the caret until the value reaches its maximum length
+func (ti *TextInput) insert(text string) {
+	for _, r := range text {
+		if ti.textInputParams.MaxLength > 0 && len(ti.value) >= ti.textInputParams.MaxLength {
+			break
+		}
+
+		if r == utf8.RuneError || r < ' ' || (ti.textInputParams.Allowed != nil && !ti.textInputParams.Allowed(string(r))) {
+			continue
+		}
+
+		ti.value = append(ti.value[:ti.caret], append([]rune{r}, ti.value[ti.caret:]...)...)
+		ti.caret++
+	}
+
+	ti.showCaret()
+}
+
+// submit stops the input and calls the submit function with the value
+func (ti *TextInput) submit() {
+	ti.done = true
+	sdl.StopTextInput()
+
+	if ti.onSubmit != nil {
+		ti.onSubmit(string(ti.value))
+	}
+}
+
+// OnSubmit gets a function that is called with the value when the player presses enter
+func (ti *TextInput) OnSubmit(fn func(string)) {
+	ti.onSubmit = fn
+}
+
+// Blink returns a func(time.Duration) bool that can be added to animation manager to blink the caret until the value is submitted
+func (ti *TextInput) Blink() func(time.Duration) bool {
+	var elapsed time.Duration
+
+	return func(dt time.Duration) bool {
+		if ti.done {
+			return true
+		}
+
+		elapsed += dt
+		if elapsed >= CARET_BLINK {
+			elapsed = 0
+			ti.caretHidden = !ti.caretHidden
+			ti.MarkDirty()
+		}
+
+		return false
+	}
+}
+
+// showCaret makes the caret visible after a change, so the player can see where the next character goes
+func (ti *TextInput) showCaret() {
+	ti.caretHidden = false
+	ti.MarkDirty()
+}
+
+func (ti *TextInput) makeParent(parent Widget) {
+	ti.parent = parent
+}
+
+func (ti *TextInput) getParent() Widget {
+	return ti.parent
+}
+
+func (ti *TextInput) setLimit(limit int) {
+	ti.textInputParams.Prompt.setLimit(limit)
+}
+
+func (ti *TextInput) MarkDirty() {
+	ti.dirty = true
+
+	if ti.parent != nil {
+		ti.parent.MarkDirty()
+	}
+}
+
+// Destroy frees the widget and stops SDL text input if the value isn't submitted
+func (ti *TextInput) Destroy() {
+	if !ti.done {
+		ti.done = true
+		sdl.StopTextInput()
+	}
+
+	if ti.drawableObject.texture != nil {
+		ti.drawableObject.texture.Destroy()
+	}
+
+	ti.textInputParams.Prompt.Destroy()
+}
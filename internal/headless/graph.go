@@ -0,0 +1,275 @@
+package headless
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/moheb2000/fufu/internal/script"
+)
+
+// GRAPH_KINDS are the story functions that are shown as nodes of the graph
+var GRAPH_KINDS = []string{"narrate", "say", "choice"}
+
+// Node is a line or a choice of the story. All paths that run the same call of the script share the node
+type Node struct {
+	ID        string   `json:"id"`
+	Kind      string   `json:"kind"`
+	File      string   `json:"file,omitempty"`
+	Line      int      `json:"line,omitempty"`
+	Label     string   `json:"label,omitempty"`
+	Character string   `json:"character,omitempty"`
+	Text      string   `json:"text,omitempty"`
+	Options   []string `json:"options,omitempty"`
+	// Ending is true if the story ends after this node in a path
+	Ending bool `json:"ending,omitempty"`
+	// DeadEnd is true if a path stops at this node without reaching the end of the story
+	DeadEnd bool `json:"deadEnd,omitempty"`
+	// Unreachable is true for calls in the script files that no path runs
+	Unreachable bool `json:"unreachable,omitempty"`
+}
+
+// Edge connects a node to the next node of a path. Option is the chosen option if From is a choice
+type Edge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Option int    `json:"option,omitempty"`
+}
+
+// DeadEnd is a path of choices that stops before the end of the story
+type DeadEnd struct {
+	Choices []int  `json:"choices"`
+	Node    string `json:"node"`
+	Reason  string `json:"reason"`
+}
+
+// Graph is the structure of the story with all paths of choices
+type Graph struct {
+	// Depth is the number of choices explored in every path. Options after them are not explored, so their lines may be reported as unreachable
+	Depth    int       `json:"depth"`
+	Nodes    []*Node   `json:"nodes"`
+	Edges    []Edge    `json:"edges"`
+	DeadEnds []DeadEnd `json:"deadEnds"`
+	nodes    map[string]*Node
+	edges    map[Edge]bool
+}
+
+// START and END are the IDs of the nodes at the start and the end of the story
+const (
+	START = "start"
+	END   = "end"
+)
+
+// BuildGraph runs every path of choices up to depth and returns the graph of the story
+func BuildGraph(p Params, depth int) (*Graph, error) {
+	g := Graph{
+		Depth: depth,
+		nodes: make(map[string]*Node),
+		edges: make(map[Edge]bool),
+	}
+	g.node(&Node{ID: START, Kind: START})
+
+	files := make(map[string]bool)
+	var order []string
+	Explore(p, depth, func(result *Result, err error) {
+		for _, file := range result.Files {
+			if !files[file] {
+				files[file] = true
+				order = append(order, file)
+			}
+		}
+
+		g.addPath(result, err, p.MaxSteps)
+	})
+
+	// Calls that no path reached are added without edges
+	loader := script.NewLoader(p.Root)
+	for _, file := range order {
+		calls, err := loader.FindCalls(file, GRAPH_KINDS...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, call := range calls {
+			id := nodeID(call.File, call.Line, call.Name)
+			if _, exists := g.nodes[id]; exists {
+				continue
+			}
+
+			g.node(&Node{
+				ID:          id,
+				Kind:        call.Name,
+				File:        call.File,
+				Line:        call.Line,
+				Unreachable: true,
+			})
+		}
+	}
+
+	return &g, nil
+}
+
+// addPath adds the nodes and edges of a run to the graph
+func (g *Graph) addPath(result *Result, err error, maxSteps int) {
+	last := g.nodes[START]
+	option := 0
+
+	for _, event := range result.Events {
+		if !slices.Contains(GRAPH_KINDS, event.Kind) {
+			continue
+		}
+
+		node := g.node(&Node{
+			ID:        nodeID(event.File, event.Line, event.Kind),
+			Kind:      event.Kind,
+			File:      event.File,
+			Line:      event.Line,
+			Label:     event.Label,
+			Character: event.Character,
+			Text:      event.Text,
+			Options:   event.Options,
+		})
+		g.edge(Edge{From: last.ID, To: node.ID, Option: option})
+
+		last = node
+		option = event.Choice
+	}
+
+	switch {
+	case result.Stopped:
+		// The rest of the path is added by the runs of its options
+	case result.Ended:
+		last.Ending = true
+		g.node(&Node{ID: END, Kind: END})
+		g.edge(Edge{From: last.ID, To: END, Option: option})
+	default:
+		reason := "choice has no options"
+		if err != nil {
+			reason = err.Error()
+		} else if result.Looped {
+			if maxSteps <= 0 {
+				maxSteps = MAX_STEPS
+			}
+			reason = fmt.Sprintf("story didn't end after %d steps", maxSteps)
+		}
+
+		last.DeadEnd = true
+		g.DeadEnds = append(g.DeadEnds, DeadEnd{
+			Choices: result.Choices,
+			Node:    last.ID,
+			Reason:  reason,
+		})
+	}
+}
+
+// node returns the node with the same ID or adds the new node to the graph
+func (g *Graph) node(n *Node) *Node {
+	if node, exists := g.nodes[n.ID]; exists {
+		return node
+	}
+
+	g.nodes[n.ID] = n
+	g.Nodes = append(g.Nodes, n)
+
+	return n
+}
+
+func (g *Graph) edge(e Edge) {
+	if !g.edges[e] {
+		g.edges[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+// WriteJSON writes the graph as JSON
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(g)
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz. Dead ends are red and unreachable lines are gray
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph story {\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	for _, n := range g.Nodes {
+		attributes := []string{fmt.Sprintf("label=\"%s\"", dotEscape(n.title()))}
+
+		switch n.Kind {
+		case START:
+			attributes = append(attributes, "shape=circle")
+		case END:
+			attributes = append(attributes, "shape=doublecircle")
+		case "choice":
+			attributes = append(attributes, "shape=diamond")
+		}
+
+		switch {
+		case n.DeadEnd:
+			attributes = append(attributes, "color=red", "fontcolor=red")
+		case n.Unreachable:
+			attributes = append(attributes, "style=dashed", "color=gray", "fontcolor=gray")
+		case n.Ending:
+			attributes = append(attributes, "peripheries=2")
+		}
+
+		fmt.Fprintf(&b, "\t\"%s\" [%s];\n", dotEscape(n.ID), strings.Join(attributes, ", "))
+	}
+
+	for _, e := range g.Edges {
+		label := ""
+		if from := g.nodes[e.From]; e.Option > 0 && e.Option <= len(from.Options) {
+			label = fmt.Sprintf(" [label=\"%d. %s\"]", e.Option, dotEscape(shorten(from.Options[e.Option-1])))
+		}
+
+		fmt.Fprintf(&b, "\t\"%s\" -> \"%s\"%s;\n", dotEscape(e.From), dotEscape(e.To), label)
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// title returns the text that is shown in the node
+func (n *Node) title() string {
+	switch {
+	case n.Unreachable:
+		return fmt.Sprintf("%s:%d\n%s", n.File, n.Line, n.Kind)
+	case n.Kind == "say":
+		return fmt.Sprintf("%s:%d\n%s: %s", n.File, n.Line, n.Character, shorten(n.Text))
+	case n.Kind == "choice":
+		return fmt.Sprintf("%s:%d\nchoice", n.File, n.Line)
+	case n.File != "":
+		return fmt.Sprintf("%s:%d\n%s", n.File, n.Line, shorten(n.Text))
+	}
+
+	return n.Kind
+}
+
+// nodeID returns the ID of the node of a call in the script
+func nodeID(file string, line int, kind string) string {
+	return fmt.Sprintf("%s:%d:%s", file, line, kind)
+}
+
+// shorten cuts long texts, so nodes of the graph stay small
+func shorten(text string) string {
+	runes := []rune(text)
+	if len(runes) > 40 {
+		return string(runes[:40]) + "..."
+	}
+
+	return text
+}
+
+// dotEscape escapes a string for a quoted DOT ID
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,86 @@
+package headless
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildGraph(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": `
+narrate("start")
+local c = choice({"end", "error", "secret"})
+if c == 2 then
+	error("broken route")
+elseif c == 3 and false then
+	narrate("never")
+end
+say(character("Fufu", "#ff0000"), "bye")
+`,
+	})
+
+	g, err := BuildGraph(*p, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := make(map[string]*Node)
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+
+	if n := nodes["main.lua:7:narrate"]; n == nil || !n.Unreachable {
+		t.Errorf("main.lua:7: expected an unreachable node; got: %v", n)
+	}
+
+	if n := nodes["main.lua:9:say"]; n == nil || !n.Ending || n.Text != "bye" {
+		t.Errorf("main.lua:9: expected an ending node with text bye; got: %v", n)
+	}
+
+	if len(g.DeadEnds) != 1 || g.DeadEnds[0].Node != "main.lua:3:choice" || !strings.Contains(g.DeadEnds[0].Reason, "broken route") {
+		t.Errorf("dead ends: expected one at main.lua:3 with the error; got: %v", g.DeadEnds)
+	}
+
+	expected := []Edge{
+		{From: START, To: "main.lua:2:narrate"},
+		{From: "main.lua:2:narrate", To: "main.lua:3:choice"},
+		{From: "main.lua:3:choice", To: "main.lua:9:say", Option: 1},
+		{From: "main.lua:9:say", To: END},
+		{From: "main.lua:3:choice", To: "main.lua:9:say", Option: 3},
+	}
+	if len(g.Edges) != len(expected) {
+		t.Fatalf("edges: expected %v; got: %v", expected, g.Edges)
+	}
+	for i := range expected {
+		if g.Edges[i] != expected[i] {
+			t.Errorf("edge %d: expected %v; got: %v", i, expected[i], g.Edges[i])
+		}
+	}
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		`"main.lua:3:choice" -> "main.lua:9:say" [label="3. secret"];`,
+		`"main.lua:9:say" [label="main.lua:9\nFufu: bye", peripheries=2];`,
+		`"main.lua:3:choice" [label="main.lua:3\nchoice", shape=diamond, color=red, fontcolor=red];`,
+	} {
+		if !strings.Contains(dot.String(), s) {
+			t.Errorf("dot: expected %s in:\n%s", s, dot.String())
+		}
+	}
+
+	var js bytes.Buffer
+	if err := g.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Graph
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil || len(decoded.Nodes) != len(g.Nodes) {
+		t.Errorf("json: expected %d nodes; got: %d %v", len(g.Nodes), len(decoded.Nodes), err)
+	}
+}
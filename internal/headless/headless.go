@@ -0,0 +1,225 @@
+// headless package runs the game script without a window. Story functions are replaced by stubs that record what the script does and check their arguments, so a game can be validated and tested in a terminal
+package headless
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/moheb2000/fufu/internal/script"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MAX_STEPS is the default number of resumes before a run is stopped, so a story that never ends doesn't run forever
+const MAX_STEPS = 10000
+
+// Params are the settings of a headless run
+type Params struct {
+	// Root is the game root directory. Script files and assets are loaded relative to it
+	Root   string
+	Script string
+	// SaveDir is the directory that fufu.fs writes to. It should be a temporary directory, so runs don't change the data of the game
+	SaveDir       string
+	Sandbox       bool
+	EngineVersion string
+	GameVersion   string
+	// Choices are the options chosen at the choices of the story in order. When there are no choices left, the first option that can be chosen is chosen
+	Choices []int
+	// StopAtChoice stops the run at the first choice after Choices instead of choosing the first option
+	StopAtChoice bool
+	MaxSteps     int
+}
+
+// Event is a call of a story function by the script
+type Event struct {
+	Kind      string   `json:"kind"`
+	File      string   `json:"file,omitempty"`
+	Line      int      `json:"line,omitempty"`
+	Label     string   `json:"label,omitempty"`
+	Character string   `json:"character,omitempty"`
+	Text      string   `json:"text,omitempty"`
+	Path      string   `json:"path,omitempty"`
+	Options   []string `json:"options,omitempty"`
+	// Available are the options of a choice event that can be chosen. Other options are hidden or disabled
+	Available []int `json:"available,omitempty"`
+	// Choice is the option chosen by a choice event
+	Choice int `json:"choice,omitempty"`
+	// Value is the text returned by an input event. Headless runs always type the default text
+	Value string `json:"value,omitempty"`
+}
+
+// Issue is a problem found in the script, like a missing file or an invalid color
+type Issue struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+	}
+
+	if i.File != "" {
+		return i.File + ": " + i.Message
+	}
+
+	return i.Message
+}
+
+// Result is what happened in a headless run
+type Result struct {
+	Events []Event
+	Issues []Issue
+	// Choices are the options chosen during the run
+	Choices []int
+	// Ended is true if the script ran to its end
+	Ended bool
+	// Stopped is true if the run stopped at a choice because of StopAtChoice. The last event is that choice
+	Stopped bool
+	// Looped is true if the story didn't end after MaxSteps
+	Looped bool
+	// Files are the script files loaded in the run
+	Files []string
+}
+
+// Run runs the script from the beginning until it ends. Script errors are returned as *script.Error and the result has the events before the error
+func Run(p *Params) (*Result, error) {
+	s := story{
+		params: p,
+		result: &Result{},
+		loader: script.NewLoader(p.Root),
+		fonts:  make(map[string]bool),
+		shown:  make(map[string]bool),
+	}
+
+	L := script.NewState(p.Sandbox)
+	defer L.Close()
+
+	// Files are recorded after the run, because require and include load them while the story runs
+	defer func() {
+		s.result.Files = s.loader.Files()
+	}()
+
+	runner, err := s.init(L)
+	if err != nil {
+		return s.result, err
+	}
+
+	maxSteps := p.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = MAX_STEPS
+	}
+
+	var args []lua.LValue
+	for step := 0; step < maxSteps; step++ {
+		s.yield = ""
+
+		_, err := runner.Resume(args...)
+		if err != nil {
+			return s.result, err
+		}
+
+		if runner.Dead() {
+			s.result.Ended = true
+			return s.result, nil
+		}
+
+		args = nil
+		if s.yield == "input" {
+			args = []lua.LValue{lua.LString(s.result.Events[len(s.result.Events)-1].Value)}
+			continue
+		}
+
+		if s.yield != "choice" {
+			continue
+		}
+
+		// The last event is the choice that yielded the script
+		event := &s.result.Events[len(s.result.Events)-1]
+		if len(event.Available) == 0 {
+			return s.result, nil
+		}
+
+		choice := event.Available[0]
+		if n := len(s.result.Choices); n < len(p.Choices) {
+			choice = p.Choices[n]
+		} else if p.StopAtChoice {
+			s.result.Stopped = true
+			return s.result, nil
+		}
+
+		if choice < 1 || choice > len(event.Options) {
+			return s.result, &script.Error{
+				File:    event.File,
+				Line:    event.Line,
+				Message: fmt.Sprintf("option %d is chosen but the choice has %d options", choice, len(event.Options)),
+			}
+		}
+
+		if !slices.Contains(event.Available, choice) {
+			return s.result, &script.Error{
+				File:    event.File,
+				Line:    event.Line,
+				Message: fmt.Sprintf("option %d is chosen but it's hidden or disabled", choice),
+			}
+		}
+
+		event.Choice = choice
+		s.result.Choices = append(s.result.Choices, choice)
+		args = []lua.LValue{lua.LNumber(choice)}
+	}
+
+	s.result.Looped = true
+	s.result.Issues = append(s.result.Issues, Issue{
+		Message: fmt.Sprintf("story didn't end after %d steps (choices: %v). It may be stuck in a loop", maxSteps, s.result.Choices),
+	})
+
+	return s.result, nil
+}
+
+// Explore runs the script once for every path of choices. Only the options that can be chosen are explored. Only the first depth choices of a path are explored and the first option is chosen after them. visit is called with the result of every run
+func Explore(p Params, depth int, visit func(*Result, error)) {
+	paths := [][]int{nil}
+
+	for len(paths) > 0 {
+		path := paths[0]
+		paths = paths[1:]
+
+		p.Choices = path
+		p.StopAtChoice = len(path) < depth
+		result, err := Run(&p)
+		visit(result, err)
+
+		if err != nil || !result.Stopped {
+			continue
+		}
+
+		for _, choice := range result.Events[len(result.Events)-1].Available {
+			paths = append(paths, append(slices.Clone(path), choice))
+		}
+	}
+}
+
+// init binds the stubs and loads the script
+func (s *story) init(L *lua.LState) (*script.Runner, error) {
+	script.OpenFS(L, s.params.Root, s.params.SaveDir)
+	s.open(L)
+
+	flow, err := script.OpenFlow(L)
+	if err != nil {
+		return nil, err
+	}
+	s.flow = flow
+
+	err = s.loader.Open(L)
+	if err != nil {
+		return nil, err
+	}
+
+	fn, err := s.loader.Load(L, s.params.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	return script.NewRunner(L, flow, fn)
+}
@@ -0,0 +1,266 @@
+package headless
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/moheb2000/fufu/internal/ease"
+	"github.com/moheb2000/fufu/internal/script"
+)
+
+// newTestParams writes the files of a game to a temporary directory and returns the params to run it
+func newTestParams(t *testing.T, files map[string]string) *Params {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &Params{
+		Root:    root,
+		Script:  "main.lua",
+		SaveDir: t.TempDir(),
+		Sandbox: true,
+	}
+}
+
+const branches = `
+label("left", function()
+	if choice({"up", "down"}) == 2 then
+		narrate("down")
+	end
+	narrate("end")
+end)
+
+bg("bg.png")
+narrate("start")
+local c = choice({"left", "right"})
+if c == 1 then
+	jump("left")
+end
+say(character("Fufu", "#ff0000"), "right")
+`
+
+func TestRun(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": branches,
+		"bg.png":   "",
+	})
+	p.Choices = []int{1, 2}
+
+	result, err := Run(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []string
+	for _, event := range result.Events {
+		kinds = append(kinds, event.Kind)
+	}
+
+	expected := []string{"bg", "narrate", "choice", "choice", "narrate", "narrate"}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("events: expected %v; got: %v", expected, kinds)
+	}
+
+	if !result.Ended || !reflect.DeepEqual(result.Choices, []int{1, 2}) {
+		t.Errorf("run: expected an ended story with choices [1 2]; got: %v %v", result.Ended, result.Choices)
+	}
+
+	last := result.Events[len(result.Events)-1]
+	if last.Label != "left" || last.File != "main.lua" || last.Line != 6 {
+		t.Errorf("last event: expected left main.lua:6; got: %s %s:%d", last.Label, last.File, last.Line)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("issues: expected none; got: %v", result.Issues)
+	}
+}
+
+func TestExplore(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": branches,
+		"bg.png":   "",
+	})
+
+	var ended [][]int
+	Explore(*p, 10, func(result *Result, err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Ended {
+			ended = append(ended, result.Choices)
+		}
+	})
+
+	expected := [][]int{{2}, {1, 1}, {1, 2}}
+	if !reflect.DeepEqual(ended, expected) {
+		t.Errorf("ended paths: expected %v; got: %v", expected, ended)
+	}
+
+	ended = nil
+	Explore(*p, 1, func(result *Result, err error) {
+		if result.Ended {
+			ended = append(ended, result.Choices)
+		}
+	})
+
+	expected = [][]int{{1, 1}, {2}}
+	if !reflect.DeepEqual(ended, expected) {
+		t.Errorf("ended paths with depth 1: expected %v; got: %v", expected, ended)
+	}
+}
+
+func TestConditionalChoice(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": `
+local has_key = false
+local c = choice({
+	{text = "Open the door", condition = function() return has_key end},
+	{text = "Fly", enabled = false},
+	"Wait",
+	"Leave",
+}, {timeout = 3})
+narrate(tostring(c))
+`,
+	})
+
+	var ended [][]int
+	Explore(*p, 10, func(result *Result, err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Ended {
+			ended = append(ended, result.Choices)
+		}
+	})
+
+	expected := [][]int{{3}, {4}}
+	if !reflect.DeepEqual(ended, expected) {
+		t.Errorf("ended paths: expected %v; got: %v", expected, ended)
+	}
+
+	p.Choices = []int{2}
+	if _, err := Run(p); err == nil {
+		t.Errorf("expected an error for choosing a disabled option")
+	}
+}
+
+func TestIssues(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": `
+bg("missing.png", {originx = "middle"})
+local c = character("Fufu", "red")
+say(c, {})
+narrate("hi", {font = font("f", "fonts/none.ttf"), font_size = "big"})
+play_music("music.txt")
+choice({"one", 2})
+narrate("{b}hi{/} {color=red}you{/} {font=title}there{/}")
+local a = character("Alice", "#ffffff", {sprites = {default = "alice.png", sad = "sad.png"}})
+show(a, "happy", {at = "top"})
+say(a, "hi", {expr = "sad"})
+bg("sad.png", {transition = {name = "mask", mask = "masks/none.png", duration = "slow"}})
+hide(a, {transition = "spin"})
+tween(a, {x = 1}, 500)
+tween("bg", {alpha = 0.5, z = 1}, 500, "fast")
+wait(-1)
+zoom(2, 500, {focus = a})
+shake("hard", 300)
+pan(100, 0, 500, {easing = 1})
+bg("candle.json")
+particles({name = "sparks", texture = "spark.png", layer = "middle", lifetime = 0})
+weather("fog", {intensity = 2})
+stop_particles()
+`,
+		"music.txt":   "",
+		"sad.png":     "",
+		"candle.json": `{"sequence": "candle_%d.png", "count": 3}`,
+	})
+
+	result, err := Run(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var issues []string
+	for _, issue := range result.Issues {
+		issues = append(issues, issue.String())
+	}
+
+	expected := []string{
+		`main.lua:2: bg: image file "missing.png" does not exist`,
+		`main.lua:2: bg: originx must be one of left, center, right; got middle`,
+		`main.lua:3: character: invalid hex color format: red`,
+		`main.lua:4: say: bad argument #2 (string expected, got table)`,
+		`main.lua:5: font: font file "fonts/none.ttf" does not exist`,
+		`main.lua:5: narrate: font file "fonts/none.ttf" does not exist`,
+		`main.lua:5: narrate: font_size must be a number; got string`,
+		`main.lua:6: play_music: audio file "music.txt" has a format that is not supported`,
+		`main.lua:7: choice: option 2 must be a string or a table; got number`,
+		`main.lua:8: narrate: {color} tag: invalid hex color format: red`,
+		`main.lua:8: narrate: font "title" of {font} tag is not created with font()`,
+		`main.lua:9: character: image file "alice.png" does not exist`,
+		`main.lua:10: show: character "Alice" has no sprite for expression "happy"`,
+		`main.lua:10: show: at must be one of left, center, right or a number; got "top"`,
+		`main.lua:12: bg: duration of transition must be a number; got string`,
+		`main.lua:12: bg: mask file "masks/none.png" does not exist`,
+		`main.lua:13: hide: transition must be one of dissolve, wipe_left, wipe_right, wipe_up, wipe_down, slide_left, slide_right, slide_up, slide_down, push_left, push_right, push_up, push_down, fade, mask; got "spin"`,
+		`main.lua:14: tween: character "Alice" is not shown`,
+		`main.lua:15: tween: property of bg tween must be one of x, y, alpha; got z`,
+		`main.lua:15: tween: easing of tween must be one of ` + strings.Join(ease.Names(), ", ") + `; got "fast"`,
+		`main.lua:16: wait: duration of wait must not be negative; got -1`,
+		`main.lua:17: zoom: character "Alice" is not shown`,
+		`main.lua:18: shake: intensity of shake must be a number of pixels that is not negative; got hard`,
+		`main.lua:19: pan: easing of pan must be a string; got number`,
+		`main.lua:20: bg: animated image "candle.json": image "candle_1.png" of the sequence does not exist`,
+		`main.lua:21: particles: layer of particles must be one of back, front; got "middle"`,
+		`main.lua:21: particles: lifetime of particles must be more than 0 and its max must not be less than its min`,
+		`main.lua:21: particles: image file "spark.png" does not exist`,
+		`main.lua:22: weather: intensity of weather must be a number from 0 to 1; got 2`,
+		`main.lua:22: weather: weather must be one of clear, dust, petals, rain, snow; got "fog"`,
+		`main.lua:23: stop_particles: bad argument #1 (string expected, got nil)`,
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("issues: expected %q; got: %q", expected, issues)
+	}
+}
+
+func TestRunError(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": "narrate(\"one\")\nchoice({\"a\"})\nundefined_function()\n",
+	})
+
+	result, err := Run(p)
+
+	var scriptErr *script.Error
+	if !errors.As(err, &scriptErr) {
+		t.Fatalf("expected a script error; got: %v", err)
+	}
+
+	if scriptErr.File != "main.lua" || scriptErr.Line != 3 {
+		t.Errorf("error position: expected main.lua:3; got: %s:%d", scriptErr.File, scriptErr.Line)
+	}
+
+	if len(result.Events) != 2 {
+		t.Errorf("events before the error: expected 2; got: %d", len(result.Events))
+	}
+
+	p.Choices = []int{3}
+	if _, err := Run(p); err == nil {
+		t.Errorf("expected an error for an option out of range")
+	}
+}
@@ -0,0 +1,613 @@
+package headless
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moheb2000/fufu/internal/color"
+	"github.com/moheb2000/fufu/internal/frames"
+	"github.com/moheb2000/fufu/internal/markup"
+	"github.com/moheb2000/fufu/internal/script"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// story keeps the state of a headless run for the stubs
+type story struct {
+	params *Params
+	result *Result
+	flow   *script.Flow
+	loader *script.Loader
+	// fonts are the names of the fonts created with font(). Markup tags can only use these fonts
+	fonts map[string]bool
+	// yield is the name of the stub that yielded the script last time
+	yield string
+	// shown are the names of the characters in the scene. Only their sprites can be tweened
+	shown map[string]bool
+}
+
+// position matches the position returned by LState.Where like "main.lua:12:"
+var position = regexp.MustCompile(`^(.+):(\d+):$`)
+
+// open adds the stubs of story functions to the lua state. They have the same arguments as the functions of the engine
+func (s *story) open(L *lua.LState) {
+	L.SetGlobal("get_engine_version", L.NewFunction(s.getEngineVersion))
+	L.SetGlobal("get_game_version", L.NewFunction(s.getGameVersion))
+	L.SetGlobal("font", L.NewFunction(s.font))
+	L.SetGlobal("character", L.NewFunction(s.character))
+	L.SetGlobal("narrate", L.NewFunction(s.narrate))
+	L.SetGlobal("say", L.NewFunction(s.say))
+	L.SetGlobal("choice", L.NewFunction(s.choice))
+	L.SetGlobal("input", L.NewFunction(s.input))
+	L.SetGlobal("bg", L.NewFunction(s.bg))
+	L.SetGlobal("show", L.NewFunction(s.show))
+	L.SetGlobal("hide", L.NewFunction(s.hide))
+	L.SetGlobal("splash", L.NewFunction(s.splash))
+	L.SetGlobal("wait", L.NewFunction(s.wait))
+	L.SetGlobal("tween", L.NewFunction(s.tween))
+	L.SetGlobal("wait_for_animations", L.NewFunction(s.waitForAnimations))
+	L.SetGlobal("shake", L.NewFunction(s.shake))
+	L.SetGlobal("zoom", L.NewFunction(s.zoom))
+	L.SetGlobal("pan", L.NewFunction(s.pan))
+	L.SetGlobal("particles", L.NewFunction(s.particles))
+	L.SetGlobal("weather", L.NewFunction(s.weather))
+	L.SetGlobal("stop_particles", L.NewFunction(s.stopParticles))
+	L.SetGlobal("play_music", L.NewFunction(s.playMusic))
+	L.SetGlobal("stop_music", L.NewFunction(s.event("stop_music")))
+	L.SetGlobal("pause_music", L.NewFunction(s.event("pause_music")))
+	L.SetGlobal("resume_music", L.NewFunction(s.event("resume_music")))
+	L.SetGlobal("play_sound", L.NewFunction(s.playAudio("play_sound")))
+	L.SetGlobal("play_voice", L.NewFunction(s.playAudio("play_voice")))
+}
+
+func (s *story) getEngineVersion(L *lua.LState) int {
+	L.Push(lua.LString(s.params.EngineVersion))
+
+	return 1
+}
+
+func (s *story) getGameVersion(L *lua.LState) int {
+	L.Push(lua.LString(s.params.GameVersion))
+
+	return 1
+}
+
+func (s *story) font(L *lua.LState) int {
+	// The engine doesn't change numbers to strings here
+	s.checkArg(L, 1, "font", lua.LTString)
+	s.checkArg(L, 2, "font", lua.LTString)
+	name, _ := L.Get(1).(lua.LString)
+	path, _ := L.Get(2).(lua.LString)
+	s.checkFile(L, "font", "font", string(path))
+	s.fonts[string(name)] = true
+
+	f := L.NewTable()
+	L.SetField(f, "name", name)
+	L.SetField(f, "path", path)
+
+	L.Push(f)
+	return 1
+}
+
+func (s *story) character(L *lua.LState) int {
+	s.checkArg(L, 1, "character", lua.LTString)
+	if s.checkArg(L, 2, "character", lua.LTString) {
+		s.checkColor(L, "character", L.ToString(2))
+	}
+	name, _ := L.Get(1).(lua.LString)
+	c, _ := L.Get(2).(lua.LString)
+
+	t := L.NewTable()
+	L.SetField(t, "name", name)
+	L.SetField(t, "color", c)
+
+	if properties := s.checkTable(L, 3, "character", true); properties != nil {
+		L.SetField(t, "sprites", properties.RawGetString("sprites"))
+
+		char, problems := script.ReadCharacter(t)
+		for _, problem := range problems {
+			s.issue(L, "character: %s", problem)
+		}
+
+		expressions := slices.Sorted(maps.Keys(char.Sprites))
+		for _, expression := range expressions {
+			s.checkFile(L, "character", "image", char.Sprites[expression])
+		}
+	}
+
+	L.Push(t)
+	return 1
+}
+
+func (s *story) narrate(L *lua.LState) int {
+	text := s.checkString(L, 1, "narrate")
+	s.checkMarkup(L, "narrate", text)
+	s.checkProperties(L, 2, "narrate", "text_color")
+
+	s.add(L, Event{Kind: "narrate", Text: text})
+
+	return s.yieldStory(L, "narrate")
+}
+
+func (s *story) say(L *lua.LState) int {
+	char := ""
+	if charTable := s.checkTable(L, 1, "say", false); charTable != nil {
+		if name, ok := charTable.RawGetString("name").(lua.LString); ok {
+			char = string(name)
+		} else {
+			s.issue(L, "say: character must have a string name")
+		}
+	}
+	text := s.checkString(L, 2, "say")
+	s.checkMarkup(L, "say", text)
+	s.checkProperties(L, 3, "say", "color")
+	if properties, ok := L.Get(3).(*lua.LTable); ok {
+		if expression := properties.RawGetString("expr"); expression != lua.LNil {
+			s.checkExpression(L, "say", L.Get(1), expression)
+		}
+	}
+
+	s.add(L, Event{Kind: "say", Character: char, Text: text})
+
+	return s.yieldStory(L, "say")
+}
+
+func (s *story) choice(L *lua.LState) int {
+	var options []string
+	var available []int
+	if t := s.checkTable(L, 1, "choice", false); t != nil {
+		properties, _ := L.Get(2).(*lua.LTable)
+		c, problems := script.ReadChoice(L, t, properties)
+		for _, problem := range problems {
+			s.issue(L, "choice: %s", problem)
+		}
+
+		// Options keep their positions in the table, so choices of the run are the values returned by choice
+		options = make([]string, t.Len())
+		for _, option := range c.Options {
+			s.checkMarkup(L, "choice", option.Text)
+			options[option.Index-1] = option.Text
+		}
+		available = c.Available()
+	}
+	s.checkProperties(L, 2, "choice", "text_color")
+	if properties, ok := L.Get(2).(*lua.LTable); ok {
+		for _, field := range []string{"disabled_color", "highlight_color"} {
+			if c, ok := properties.RawGetString(field).(lua.LString); ok {
+				s.checkColor(L, "choice", string(c))
+			}
+		}
+	}
+
+	s.add(L, Event{Kind: "choice", Options: options, Available: available})
+
+	return s.yieldStory(L, "choice")
+}
+
+func (s *story) input(L *lua.LState) int {
+	prompt := s.checkString(L, 1, "input")
+	s.checkMarkup(L, "input", prompt)
+
+	value := ""
+	if properties := s.checkTable(L, 2, "input", true); properties != nil {
+		in, problems := script.ReadInput(properties)
+		for _, problem := range problems {
+			s.issue(L, "input: %s", problem)
+		}
+		value = in.Default
+
+		if c := properties.RawGetString("text_color"); c != lua.LNil {
+			if cs, ok := c.(lua.LString); ok {
+				s.checkColor(L, "input", string(cs))
+			} else {
+				s.issue(L, "input: text_color must be a string; got %s", c.Type())
+			}
+		}
+	}
+
+	s.add(L, Event{Kind: "input", Text: prompt, Value: value})
+
+	return s.yieldStory(L, "input")
+}
+
+func (s *story) bg(L *lua.LState) int {
+	path := s.checkString(L, 1, "bg")
+	s.checkFile(L, "bg", "image", path)
+
+	if properties := s.checkTable(L, 2, "bg", true); properties != nil {
+		_, problems := script.ReadOrigin(properties)
+		for _, problem := range problems {
+			s.issue(L, "bg: %s", problem)
+		}
+
+		if f := properties.RawGetString("fade"); f != lua.LNil && f.Type() != lua.LTBool {
+			s.issue(L, "bg: fade must be a boolean; got %s", f.Type())
+		}
+
+		s.checkTransition(L, "bg", properties)
+	}
+
+	s.add(L, Event{Kind: "bg", Path: path})
+
+	return s.yieldStory(L, "bg")
+}
+
+func (s *story) show(L *lua.LState) int {
+	char := ""
+	if charTable := s.checkTable(L, 1, "show", false); charTable != nil {
+		c, _ := script.ReadCharacter(charTable)
+		char = c.Name
+	}
+
+	// Sprites that are shown again keep their expression, so only expressions that are set can be checked
+	expression := ""
+	if e := L.Get(2); e != lua.LNil {
+		s.checkExpression(L, "show", L.Get(1), e)
+		expression = e.String()
+	}
+
+	if properties := s.checkTable(L, 3, "show", true); properties != nil {
+		_, problems := script.ReadShow(properties, script.Show{})
+		for _, problem := range problems {
+			s.issue(L, "show: %s", problem)
+		}
+
+		s.checkTransition(L, "show", properties)
+	}
+
+	s.add(L, Event{Kind: "show", Character: char, Value: expression})
+	s.shown[char] = true
+
+	return 0
+}
+
+func (s *story) hide(L *lua.LState) int {
+	char := ""
+	if charTable := s.checkTable(L, 1, "hide", false); charTable != nil {
+		c, _ := script.ReadCharacter(charTable)
+		char = c.Name
+	}
+
+	if properties := s.checkTable(L, 2, "hide", true); properties != nil {
+		s.checkTransition(L, "hide", properties)
+	}
+
+	s.add(L, Event{Kind: "hide", Character: char})
+	delete(s.shown, char)
+
+	return 0
+}
+
+func (s *story) splash(L *lua.LState) int {
+	path := s.checkString(L, 1, "splash")
+	s.checkFile(L, "splash", "image", path)
+	s.checkColor(L, "splash", s.checkString(L, 2, "splash"))
+	s.checkArg(L, 3, "splash", lua.LTNumber)
+
+	s.add(L, Event{Kind: "splash", Path: path})
+
+	return s.yieldStory(L, "splash")
+}
+
+func (s *story) wait(L *lua.LState) int {
+	_, problems := script.ReadWait(L.Get(1))
+	for _, problem := range problems {
+		s.issue(L, "wait: %s", problem)
+	}
+
+	return s.yieldStory(L, "wait")
+}
+
+func (s *story) tween(L *lua.LState) int {
+	t, problems := script.ReadTween(L.Get(1), L.Get(2), L.Get(3), L.Get(4))
+	for _, problem := range problems {
+		s.issue(L, "tween: %s", problem)
+	}
+
+	if t != nil && t.Target == "sprite" && !s.shown[t.Character] {
+		s.issue(L, "tween: character %q is not shown", t.Character)
+	}
+
+	return 0
+}
+
+func (s *story) waitForAnimations(L *lua.LState) int {
+	return s.yieldStory(L, "wait_for_animations")
+}
+
+func (s *story) shake(L *lua.LState) int {
+	_, problems := script.ReadShake(L.Get(1), L.Get(2))
+	for _, problem := range problems {
+		s.issue(L, "shake: %s", problem)
+	}
+
+	return 0
+}
+
+func (s *story) zoom(L *lua.LState) int {
+	z, problems := script.ReadZoom(L.Get(1), L.Get(2), s.checkTable(L, 3, "zoom", true))
+	for _, problem := range problems {
+		s.issue(L, "zoom: %s", problem)
+	}
+
+	if z.Character != "" && !s.shown[z.Character] {
+		s.issue(L, "zoom: character %q is not shown", z.Character)
+	}
+
+	return 0
+}
+
+func (s *story) pan(L *lua.LState) int {
+	_, problems := script.ReadPan(L.Get(1), L.Get(2), L.Get(3), s.checkTable(L, 4, "pan", true))
+	for _, problem := range problems {
+		s.issue(L, "pan: %s", problem)
+	}
+
+	return 0
+}
+
+func (s *story) particles(L *lua.LState) int {
+	c, problems := script.ReadParticles(s.checkTable(L, 1, "particles", false))
+	for _, problem := range problems {
+		s.issue(L, "particles: %s", problem)
+	}
+
+	s.checkFile(L, "particles", "image", c.Texture)
+
+	return 0
+}
+
+func (s *story) weather(L *lua.LState) int {
+	_, problems := script.ReadWeather(L.Get(1), s.checkTable(L, 2, "weather", true))
+	for _, problem := range problems {
+		s.issue(L, "weather: %s", problem)
+	}
+
+	return 0
+}
+
+func (s *story) stopParticles(L *lua.LState) int {
+	s.checkString(L, 1, "stop_particles")
+
+	return 0
+}
+
+func (s *story) playMusic(L *lua.LState) int {
+	path := s.checkString(L, 1, "play_music")
+	s.checkFile(L, "play_music", "audio", path)
+	if loop := L.Get(2); loop != lua.LNil && loop.Type() != lua.LTBool {
+		s.issue(L, "play_music: bad argument #2 (boolean expected, got %s)", loop.Type())
+	}
+
+	s.add(L, Event{Kind: "play_music", Path: path})
+
+	return 0
+}
+
+// playAudio returns the stub of play_sound or play_voice
+func (s *story) playAudio(name string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		path := s.checkString(L, 1, name)
+		s.checkFile(L, name, "audio", path)
+
+		s.add(L, Event{Kind: name, Path: path})
+
+		return 0
+	}
+}
+
+// event returns a stub that only records its call
+func (s *story) event(name string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		s.add(L, Event{Kind: name})
+
+		return 0
+	}
+}
+
+// yieldStory records the name of the stub and yields the script
+func (s *story) yieldStory(L *lua.LState, name string) int {
+	s.yield = name
+
+	return L.Yield(lua.LNil)
+}
+
+// add records the event with the position of its call in the script
+func (s *story) add(L *lua.LState, event Event) {
+	event.File, event.Line = where(L)
+	event.Label = s.flow.Current
+
+	s.result.Events = append(s.result.Events, event)
+}
+
+// issue records a problem at the position of the current call in the script
+func (s *story) issue(L *lua.LState, format string, args ...any) {
+	file, line := where(L)
+
+	s.result.Issues = append(s.result.Issues, Issue{
+		File:    file,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkArg checks the type of the argument n
+func (s *story) checkArg(L *lua.LState, n int, fn string, typ lua.LValueType) bool {
+	if L.Get(n).Type() == typ {
+		return true
+	}
+
+	s.issue(L, "%s: bad argument #%d (%s expected, got %s)", fn, n, typ, L.Get(n).Type())
+
+	return false
+}
+
+// checkString returns the argument n as a string. Numbers are changed to strings like the engine does
+func (s *story) checkString(L *lua.LState, n int, fn string) string {
+	switch v := L.Get(n).(type) {
+	case lua.LString:
+		return string(v)
+	case lua.LNumber:
+		return v.String()
+	}
+
+	s.issue(L, "%s: bad argument #%d (string expected, got %s)", fn, n, L.Get(n).Type())
+
+	return ""
+}
+
+// checkTable returns the argument n as a table. If optional is true, the argument can be nil too
+func (s *story) checkTable(L *lua.LState, n int, fn string, optional bool) *lua.LTable {
+	v := L.Get(n)
+	if t, ok := v.(*lua.LTable); ok {
+		return t
+	}
+
+	if !optional || v != lua.LNil {
+		s.issue(L, "%s: bad argument #%d (table expected, got %s)", fn, n, v.Type())
+	}
+
+	return nil
+}
+
+// checkProperties checks the text properties of narrate, say and choice
+func (s *story) checkProperties(L *lua.LState, n int, fn string, colorField string) {
+	properties := s.checkTable(L, n, fn, true)
+	if properties == nil {
+		return
+	}
+
+	if c := properties.RawGetString(colorField); c != lua.LNil {
+		if cs, ok := c.(lua.LString); ok {
+			s.checkColor(L, fn, string(cs))
+		} else {
+			s.issue(L, "%s: %s must be a string; got %s", fn, colorField, c.Type())
+		}
+	}
+
+	if f := properties.RawGetString("font"); f != lua.LNil {
+		ft, ok := f.(*lua.LTable)
+		if !ok {
+			s.issue(L, "%s: font must be a table created by font(); got %s", fn, f.Type())
+		} else if fp, ok := ft.RawGetString("path").(lua.LString); ok {
+			s.checkFile(L, fn, "font", string(fp))
+		} else {
+			s.issue(L, "%s: font must have a string path", fn)
+		}
+	}
+
+	if fs := properties.RawGetString("font_size"); fs != lua.LNil && fs.Type() != lua.LTNumber {
+		s.issue(L, "%s: font_size must be a number; got %s", fn, fs.Type())
+	}
+}
+
+// checkMarkup checks the inline tags of a text. Fonts of the tags must be created before the text is shown
+func (s *story) checkMarkup(L *lua.LState, fn string, text string) {
+	for _, problem := range markup.Check(text) {
+		s.issue(L, "%s: %s", fn, problem)
+	}
+
+	for _, span := range markup.Parse(text) {
+		if name := span.Style.Font; name != "" && !s.fonts[name] {
+			s.issue(L, "%s: font %q of {font} tag is not created with font()", fn, name)
+		}
+	}
+}
+
+// checkExpression checks that the character has a sprite for the expression
+func (s *story) checkExpression(L *lua.LState, fn string, char lua.LValue, expression lua.LValue) {
+	e, ok := expression.(lua.LString)
+	if !ok {
+		s.issue(L, "%s: expression must be a string; got %s", fn, expression.Type())
+		return
+	}
+
+	charTable, ok := char.(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	c, problems := script.ReadCharacter(charTable)
+	if len(problems) > 0 {
+		return
+	}
+
+	if _, ok := c.Sprites[string(e)]; !ok {
+		s.issue(L, "%s: character %q has no sprite for expression %q", fn, c.Name, string(e))
+	}
+}
+
+// checkTransition checks the transition property of bg, show or hide and the mask image of mask transitions
+func (s *story) checkTransition(L *lua.LState, fn string, properties *lua.LTable) {
+	transition, problems := script.ReadTransition(properties.RawGetString("transition"))
+	for _, problem := range problems {
+		s.issue(L, "%s: %s", fn, problem)
+	}
+
+	if transition != nil {
+		s.checkFile(L, fn, "mask", transition.Mask)
+	}
+}
+
+// checkColor checks that c is a hex color like "#ff0000"
+func (s *story) checkColor(L *lua.LState, fn string, c string) {
+	if _, err := color.ParseHex(c); err != nil {
+		s.issue(L, "%s: %v", fn, err)
+	}
+}
+
+// checkFile checks that the file exists in the game. Audio files must also have a format that the audio package can play
+func (s *story) checkFile(L *lua.LState, fn string, kind string, path string) {
+	if path == "" {
+		return
+	}
+
+	// Paths are relative to the game root, because the engine runs in that directory
+	full := path
+	if !filepath.IsAbs(full) {
+		full = filepath.Join(s.params.Root, full)
+	}
+
+	info, err := os.Stat(full)
+	if err != nil || info.IsDir() {
+		s.issue(L, "%s: %s file %q does not exist", fn, kind, path)
+		return
+	}
+
+	if kind == "audio" {
+		switch strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")) {
+		case "mp3", "wav", "ogg", "flac":
+		default:
+			s.issue(L, "%s: audio file %q has a format that is not supported", fn, path)
+		}
+	}
+
+	// Animated images are JSON files and their frames must exist too
+	if kind == "image" && frames.IsAnimation(path) {
+		if _, err := frames.Read(full); err != nil {
+			// Errors of the file have its full path and the path of the script is enough
+			if e := errors.Unwrap(err); e != nil {
+				err = e
+			}
+			s.issue(L, "%s: animated image %q: %v", fn, path, err)
+		}
+	}
+}
+
+// where returns the file and line of the script that called the current go function
+func where(L *lua.LState) (string, int) {
+	m := position.FindStringSubmatch(L.Where(1))
+	if m == nil {
+		return "", 0
+	}
+
+	line, _ := strconv.Atoi(m[2])
+
+	return m[1], line
+}
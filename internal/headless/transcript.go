@@ -0,0 +1,180 @@
+package headless
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TRANSCRIPT_KINDS are the story functions that are written in transcripts
+var TRANSCRIPT_KINDS = []string{"narrate", "say", "choice", "input", "bg", "show", "hide", "play_music", "stop_music"}
+
+// ErrChoices is returned when the choices of a playthrough don't match the choices of the story
+var ErrChoices = errors.New("choices don't match the story")
+
+// Playthrough runs the story with exactly the given choices and returns its transcript. The story must end after the last choice
+func Playthrough(p Params, choices []int) (string, error) {
+	p.Choices = choices
+	p.StopAtChoice = true
+
+	result, err := Run(&p)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case result.Stopped:
+		return "", fmt.Errorf("%w: story has more choices after %v", ErrChoices, choices)
+	case result.Looped:
+		return "", fmt.Errorf("story didn't end with choices %v", choices)
+	case len(result.Choices) < len(choices):
+		return "", fmt.Errorf("%w: story ended after %d of %d choices", ErrChoices, len(result.Choices), len(choices))
+	}
+
+	return Transcript(result.Events), nil
+}
+
+// Transcript returns the events of a run as text with one event in every line
+func Transcript(events []Event) string {
+	var b strings.Builder
+
+	for _, event := range events {
+		if !slices.Contains(TRANSCRIPT_KINDS, event.Kind) {
+			continue
+		}
+
+		switch event.Kind {
+		case "narrate":
+			fmt.Fprintf(&b, "narrate %q\n", event.Text)
+		case "say":
+			fmt.Fprintf(&b, "say %q %q\n", event.Character, event.Text)
+		case "choice":
+			option := ""
+			if event.Choice > 0 && event.Choice <= len(event.Options) {
+				option = event.Options[event.Choice-1]
+			}
+			fmt.Fprintf(&b, "choice %d %q\n", event.Choice, option)
+		case "input":
+			fmt.Fprintf(&b, "input %q %q\n", event.Text, event.Value)
+		case "show":
+			fmt.Fprintf(&b, "show %q %q\n", event.Character, event.Value)
+		case "hide":
+			fmt.Fprintf(&b, "hide %q\n", event.Character)
+		case "bg", "play_music":
+			fmt.Fprintf(&b, "%s %q\n", event.Kind, event.Path)
+		default:
+			fmt.Fprintln(&b, event.Kind)
+		}
+	}
+
+	return b.String()
+}
+
+// Golden is a saved playthrough. The file has the choices in the first line like "choices: 1 2" and the transcript after it
+type Golden struct {
+	Choices    []int
+	Transcript string
+}
+
+// ReadGolden reads a golden file
+func ReadGolden(path string) (*Golden, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header, transcript, _ := strings.Cut(string(data), "\n")
+	fields, ok := strings.CutPrefix(strings.TrimSpace(header), "choices:")
+	if !ok {
+		return nil, fmt.Errorf("%s: first line must be the choices like \"choices: 1 2\"", path)
+	}
+
+	g := Golden{Transcript: transcript}
+	for _, field := range strings.Fields(fields) {
+		choice, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid choice %q", path, field)
+		}
+		g.Choices = append(g.Choices, choice)
+	}
+
+	return &g, nil
+}
+
+// Write writes the golden file and creates its directory if it doesn't exist
+func (g *Golden) Write(path string) error {
+	choices := make([]string, len(g.Choices))
+	for i, choice := range g.Choices {
+		choices[i] = strconv.Itoa(choice)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data := fmt.Sprintf("choices: %s\n%s", strings.Join(choices, " "), g.Transcript)
+
+	return os.WriteFile(path, []byte(data), 0o644)
+}
+
+// DiffError is returned when the transcript of a playthrough is different from its golden file
+type DiffError struct {
+	Path string
+	// Line is the first different line in the golden file
+	Line     int
+	Expected string
+	Got      string
+}
+
+func (e *DiffError) Error() string {
+	return fmt.Sprintf("%s:%d: transcript is different\n\texpected: %s\n\tgot:      %s", e.Path, e.Line, e.Expected, e.Got)
+}
+
+// CheckGolden runs the choices of the golden file and compares the transcript with it. If update is true, the golden file is written with the new transcript instead
+func CheckGolden(p Params, path string, update bool) error {
+	g, err := ReadGolden(path)
+	if err != nil {
+		return err
+	}
+
+	transcript, err := Playthrough(p, g.Choices)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if update {
+		g.Transcript = transcript
+		return g.Write(path)
+	}
+
+	return diff(path, g.Transcript, transcript)
+}
+
+// diff returns the first different line of two transcripts as a DiffError
+func diff(path string, expected string, got string) error {
+	expectedLines := strings.Split(strings.TrimRight(expected, "\n"), "\n")
+	gotLines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+
+	for i := 0; i < max(len(expectedLines), len(gotLines)); i++ {
+		e := "(end of transcript)"
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+
+		g := "(end of transcript)"
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+
+		if e != g {
+			// The first line of the golden file is the choices
+			return &DiffError{Path: path, Line: i + 2, Expected: e, Got: g}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package headless
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlaythrough(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": branches,
+		"bg.png":   "",
+	})
+
+	transcript, err := Playthrough(*p, []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `bg "bg.png"
+narrate "start"
+choice 1 "left"
+choice 2 "down"
+narrate "down"
+narrate "end"
+`
+	if transcript != expected {
+		t.Errorf("transcript: expected:\n%s\ngot:\n%s", expected, transcript)
+	}
+
+	if _, err := Playthrough(*p, []int{1}); !errors.Is(err, ErrChoices) {
+		t.Errorf("missing choices: expected ErrChoices; got: %v", err)
+	}
+
+	if _, err := Playthrough(*p, []int{2, 1}); !errors.Is(err, ErrChoices) {
+		t.Errorf("extra choices: expected ErrChoices; got: %v", err)
+	}
+}
+
+func TestCheckGolden(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": branches,
+		"bg.png":   "",
+	})
+	path := filepath.Join(t.TempDir(), "tests", "right.golden")
+
+	g := Golden{Choices: []int{2}}
+	if err := g.Write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	var diffErr *DiffError
+	if err := CheckGolden(*p, path, false); !errors.As(err, &diffErr) || diffErr.Line != 2 || diffErr.Got != `bg "bg.png"` {
+		t.Errorf("empty golden file: expected a difference at line 2; got: %v", err)
+	}
+
+	if err := CheckGolden(*p, path, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckGolden(*p, path, false); err != nil {
+		t.Errorf("updated golden file: expected no error; got: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	expected := "choices: 2\nbg \"bg.png\"\nnarrate \"start\"\nchoice 2 \"right\"\nsay \"Fufu\" \"right\"\n"
+	if string(data) != expected {
+		t.Errorf("golden file: expected %q; got: %q", expected, data)
+	}
+}
+
+func TestInputTranscript(t *testing.T) {
+	p := newTestParams(t, map[string]string{
+		"main.lua": `
+local name = input("What is your name?", {default = "Fufu", max_length = 10})
+narrate("Hello " .. name)
+`,
+	})
+
+	transcript, err := Playthrough(*p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `input "What is your name?" "Fufu"
+narrate "Hello Fufu"
+`
+	if transcript != expected {
+		t.Errorf("transcript: expected:\n%s\ngot:\n%s", expected, transcript)
+	}
+}
@@ -0,0 +1,87 @@
+package locale
+
+import (
+	"fmt"
+
+	"github.com/moheb2000/fufu/internal/script"
+	"github.com/yuin/gopher-lua/ast"
+)
+
+// Extract finds the texts of narrate, say, choice, input and character calls in the script file and the files it loads. Only constant strings can be translated, so the positions of other texts are returned as skipped
+func Extract(loader *script.Loader, file string) (entries []Entry, skipped []string, err error) {
+	files, err := loader.FindFiles(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := make(map[string]int)
+	add := func(call script.Call, expr ast.Expr) {
+		place := fmt.Sprintf("%s:%d", call.File, call.Line)
+
+		s, ok := expr.(*ast.StringExpr)
+		if !ok {
+			skipped = append(skipped, fmt.Sprintf("%s: %s text is not a constant string", place, call.Name))
+			return
+		}
+
+		key := Key(s.Value)
+		if i, exists := index[key]; exists {
+			entries[i].Places = append(entries[i].Places, place)
+			return
+		}
+
+		index[key] = len(entries)
+		entries = append(entries, Entry{
+			Key:    key,
+			Source: s.Value,
+			Places: []string{place},
+		})
+	}
+
+	for _, f := range files {
+		calls, err := loader.FindCalls(f, "narrate", "say", "choice", "input", "character")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, call := range calls {
+			switch call.Name {
+			case "narrate", "input", "character":
+				if len(call.Args) > 0 {
+					add(call, call.Args[0])
+				}
+			case "say":
+				if len(call.Args) > 1 {
+					add(call, call.Args[1])
+				}
+			case "choice":
+				if len(call.Args) == 0 {
+					continue
+				}
+
+				options, ok := call.Args[0].(*ast.TableExpr)
+				if !ok {
+					add(call, call.Args[0])
+					continue
+				}
+
+				for _, field := range options.Fields {
+					// Options can be tables with a text and a tooltip
+					option, ok := field.Value.(*ast.TableExpr)
+					if !ok {
+						add(call, field.Value)
+						continue
+					}
+
+					for _, f := range option.Fields {
+						if key, ok := f.Key.(*ast.StringExpr); ok && (key.Value == "text" || key.Value == "tooltip") {
+							add(call, f.Value)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return entries, skipped, nil
+}
@@ -0,0 +1,114 @@
+// locale package translates the texts of the game script with string tables of every language
+package locale
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// DIR is the directory of string tables in the game root
+const DIR = "locales"
+
+// TEMPLATE is the name of the string table that has all texts without translations
+const TEMPLATE = "template"
+
+// Entry is a text of the script and its translation
+type Entry struct {
+	Key         string `json:"key"`
+	Source      string `json:"source"`
+	Translation string `json:"translation"`
+	// Places are the positions of the text in the script like "main.lua:12". They help translators to find the context of the text
+	Places []string `json:"places,omitempty"`
+}
+
+// Table is the string table of a language. Every language has a file like "locales/fa.json"
+type Table struct {
+	Language string `json:"language"`
+	// Font is the default font of the language. It's used instead of the default font of config.json, because some languages need fonts with their own letters
+	Font         string  `json:"font,omitempty"`
+	Strings      []Entry `json:"strings"`
+	translations map[string]string
+}
+
+// Key returns the stable key of a source text. The key is the same for the same text in every file, so it's translated once
+func Key(text string) string {
+	sum := sha1.Sum([]byte(text))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// Load reads the string table of the language inside dir
+func Load(dir string, language string) (*Table, error) {
+	return Read(filepath.Join(dir, language+".json"))
+}
+
+// Read reads a string table file
+func Read(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+
+	t.translations = make(map[string]string)
+	for _, entry := range t.Strings {
+		if entry.Translation != "" {
+			t.translations[entry.Key] = entry.Translation
+		}
+	}
+
+	return &t, nil
+}
+
+// Write stores the string table in a file
+func (t *Table) Write(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Translate returns the translation of text. If the table is nil or the text isn't translated, the source text is returned
+func (t *Table) Translate(text string) string {
+	if t == nil {
+		return text
+	}
+
+	if translation, ok := t.translations[Key(text)]; ok {
+		return translation
+	}
+
+	return text
+}
+
+// Merge replaces the strings of the table with entries and keeps the translations of the texts that still exist in the script
+func (t *Table) Merge(entries []Entry) {
+	old := make(map[string]string)
+	for _, entry := range t.Strings {
+		old[entry.Key] = entry.Translation
+	}
+
+	t.Strings = make([]Entry, len(entries))
+	t.translations = make(map[string]string)
+	for i, entry := range entries {
+		entry.Translation = old[entry.Key]
+		t.Strings[i] = entry
+
+		if entry.Translation != "" {
+			t.translations[entry.Key] = entry.Translation
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package locale
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/moheb2000/fufu/internal/script"
+)
+
+func TestExtract(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.lua": `
+local fufu = character("Fufu", "#ff0000")
+narrate("Hello")
+say(fufu, "Hello")
+choice({"Yes", {text = "No", tooltip = "Maybe", enabled = false}})
+narrate("Day " .. day)
+include("end.lua")
+`,
+		"end.lua": `narrate("The end")`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, skipped, err := Extract(script.NewLoader(root), "main.lua")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sources []string
+	for _, entry := range entries {
+		sources = append(sources, entry.Source)
+	}
+
+	expected := []string{"Fufu", "Hello", "Yes", "No", "Maybe", "The end"}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Errorf("sources: expected %v; got: %v", expected, sources)
+	}
+
+	if places := entries[1].Places; !reflect.DeepEqual(places, []string{"main.lua:3", "main.lua:4"}) {
+		t.Errorf("places of Hello: expected [main.lua:3 main.lua:4]; got: %v", places)
+	}
+
+	if !reflect.DeepEqual(skipped, []string{"main.lua:6: narrate text is not a constant string"}) {
+		t.Errorf("skipped: expected the text at main.lua:6; got: %v", skipped)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locales", "fa.json")
+
+	table := Table{Language: "fa", Font: "assets/Vazirmatn.ttf"}
+	table.Merge([]Entry{{Key: Key("Hello"), Source: "Hello"}, {Key: Key("Bye"), Source: "Bye"}})
+	table.Strings[0].Translation = "سلام"
+	if err := table.Write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(filepath.Dir(path), "fa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := loaded.Translate("Hello"); got != "سلام" {
+		t.Errorf("translated text: expected سلام; got: %s", got)
+	}
+
+	if got := loaded.Translate("Bye"); got != "Bye" {
+		t.Errorf("untranslated text: expected Bye; got: %s", got)
+	}
+
+	var none *Table
+	if got := none.Translate("Hello"); got != "Hello" {
+		t.Errorf("nil table: expected Hello; got: %s", got)
+	}
+
+	// Texts that are removed from the script lose their translations and new texts are added
+	loaded.Merge([]Entry{{Key: Key("New"), Source: "New"}, {Key: Key("Hello"), Source: "Hello"}})
+	if len(loaded.Strings) != 2 || loaded.Strings[1].Translation != "سلام" || loaded.Translate("Hello") != "سلام" || loaded.Font != "assets/Vazirmatn.ttf" {
+		t.Errorf("merged table: expected New and the translation of Hello; got: %+v", loaded)
+	}
+}
@@ -0,0 +1,117 @@
+package markup
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Lines breaks the spans into lines that are not wider than limit. A limit of 0 only breaks lines at new lines. Lines are broken at spaces, so a word with more than one style stays in one line. measure returns the width of a span when it's rendered
+func Lines(spans []Span, limit int, measure func(Span) int) [][]Span {
+	var lines [][]Span
+	var line []Span
+	var word []Span
+	width := 0
+	// spaces are kept until the next word, so lines don't end with spaces
+	var spaces []Span
+
+	addWord := func() {
+		if len(word) == 0 {
+			return
+		}
+
+		w := 0
+		for _, span := range append(spaces, word...) {
+			w += measure(span)
+		}
+
+		if limit > 0 && len(line) > 0 && width+w > limit {
+			lines = append(lines, merge(line))
+			line = nil
+			width = 0
+			spaces = nil
+
+			w = 0
+			for _, span := range word {
+				w += measure(span)
+			}
+		}
+
+		line = append(line, spaces...)
+		line = append(line, word...)
+		width += w
+		spaces = nil
+		word = nil
+	}
+
+	for _, span := range spans {
+		for i, paragraph := range strings.Split(span.Text, "\n") {
+			if i > 0 {
+				addWord()
+				lines = append(lines, merge(line))
+				line = nil
+				width = 0
+				spaces = nil
+			}
+
+			for j, part := range strings.Split(paragraph, " ") {
+				if j > 0 {
+					addWord()
+					spaces = append(spaces, Span{Text: " ", Style: span.Style})
+				}
+
+				if part != "" {
+					word = append(word, Span{Text: part, Style: span.Style})
+				}
+			}
+		}
+	}
+	addWord()
+
+	return append(lines, merge(line))
+}
+
+// merge joins the spans with the same style that are next to each other
+func merge(spans []Span) []Span {
+	var merged []Span
+
+	for _, span := range spans {
+		if n := len(merged); n > 0 && merged[n-1].Style == span.Style {
+			merged[n-1].Text += span.Text
+			continue
+		}
+
+		merged = append(merged, span)
+	}
+
+	return merged
+}
+
+// Truncate returns the lines with only the first n glyphs of them. Spaces are not glyphs, so they are kept until the next glyph is shown
+func Truncate(lines [][]Span, n int) [][]Span {
+	truncated := make([][]Span, len(lines))
+
+	for i, line := range lines {
+		for _, span := range line {
+			if n <= 0 {
+				return truncated
+			}
+
+			end := len(span.Text)
+			for j, r := range span.Text {
+				if unicode.IsSpace(r) {
+					continue
+				}
+
+				if n == 0 {
+					end = j
+					break
+				}
+				n--
+			}
+
+			truncated[i] = append(truncated[i], Span{Text: span.Text[:end], Style: span.Style})
+		}
+	}
+
+	return truncated
+}
@@ -0,0 +1,257 @@
+// markup package parses the inline tags of the texts of the script, like "{b}bold{/} and {color=#f00}red{/}", into runs of styled text
+package markup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/moheb2000/fufu/internal/color"
+)
+
+// Style is the style of a part of a text. Zero values use the style of the text widget
+type Style struct {
+	Bold   bool
+	Italic bool
+	// Color is a hex color like "#ff0000"
+	Color string
+	Size  int
+	// Font is the name of a font created with the font function of the script
+	Font string
+}
+
+// Span is a part of a text with a single style
+type Span struct {
+	Text  string
+	Style Style
+}
+
+// Pause is a {w} tag that stops the typewriter reveal of a text
+type Pause struct {
+	// At is the number of glyphs before the tag
+	At       int
+	Duration time.Duration
+}
+
+// tag is an opening tag that changes the style until its {/}
+type tag struct {
+	name  string
+	value string
+}
+
+// Escape returns the text with its "{" written as "{{", so the text is shown as it is inside markup
+func Escape(text string) string {
+	return strings.ReplaceAll(text, "{", "{{")
+}
+
+// Parse returns the styled runs of a text. {/} closes the last opened tag and "{{" is written as "{". {w} tags don't change the style and tags that are not valid are kept as text
+func Parse(text string) []Span {
+	var spans []Span
+	var stack []Style
+	style := Style{}
+	var b strings.Builder
+
+	flush := func() {
+		if b.Len() > 0 {
+			spans = append(spans, Span{Text: b.String(), Style: style})
+			b.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' {
+			b.WriteByte(text[i])
+			continue
+		}
+
+		if strings.HasPrefix(text[i:], "{{") {
+			b.WriteByte('{')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(text[i:], '}')
+		if end < 0 {
+			b.WriteByte('{')
+			continue
+		}
+
+		t, err := parseTag(text[i+1 : i+end])
+		if err != nil {
+			b.WriteByte('{')
+			continue
+		}
+
+		flush()
+		i += end
+
+		if t.name == "w" {
+			continue
+		}
+
+		if t.name == "/" {
+			if len(stack) > 0 {
+				style = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		stack = append(stack, style)
+		style = t.apply(style)
+	}
+	flush()
+
+	return spans
+}
+
+// Plain returns the text without its tags
+func Plain(text string) string {
+	var b strings.Builder
+	for _, span := range Parse(text) {
+		b.WriteString(span.Text)
+	}
+
+	return b.String()
+}
+
+// Pauses returns the {w} tags of a text in order
+func Pauses(text string) []Pause {
+	var pauses []Pause
+	at := 0
+
+	for i := 0; i < len(text); i++ {
+		if strings.HasPrefix(text[i:], "{{") {
+			at++
+			i++
+			continue
+		}
+
+		if text[i] == '{' {
+			if end := strings.IndexByte(text[i:], '}'); end >= 0 {
+				if t, err := parseTag(text[i+1 : i+end]); err == nil {
+					if t.name == "w" {
+						seconds, _ := strconv.ParseFloat(t.value, 64)
+						pauses = append(pauses, Pause{At: at, Duration: time.Duration(seconds * float64(time.Second))})
+					}
+					i += end
+					continue
+				}
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			at++
+		}
+		i += size - 1
+	}
+
+	return pauses
+}
+
+// Glyphs returns the number of characters of text that are not spaces. The typewriter reveal of a text shows one glyph at a time
+func Glyphs(text string) int {
+	n := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Check returns the problems of the tags in a text, like unknown tags or invalid colors
+func Check(text string) []string {
+	var problems []string
+
+	for i := 0; i < len(text); i++ {
+		if strings.HasPrefix(text[i:], "{{") {
+			i++
+			continue
+		}
+
+		if text[i] != '{' {
+			continue
+		}
+
+		end := strings.IndexByte(text[i:], '}')
+		if end < 0 {
+			problems = append(problems, "tag is not closed with }")
+			break
+		}
+
+		if _, err := parseTag(text[i+1 : i+end]); err != nil {
+			problems = append(problems, err.Error())
+		}
+		i += end
+	}
+
+	return problems
+}
+
+// parseTag returns the tag inside the braces
+func parseTag(s string) (tag, error) {
+	name, value, hasValue := strings.Cut(s, "=")
+	t := tag{name: name, value: value}
+
+	switch name {
+	case "/", "b", "i":
+		if hasValue {
+			return t, fmt.Errorf("{%s} tag doesn't have a value", name)
+		}
+	case "color":
+		if _, err := color.ParseHex(value); err != nil {
+			return t, fmt.Errorf("{color} tag: %v", err)
+		}
+	case "w":
+		if seconds, err := strconv.ParseFloat(value, 64); err != nil || seconds <= 0 {
+			return t, fmt.Errorf("{w} tag needs a positive number of seconds; got %q", value)
+		}
+	case "size":
+		if size, err := strconv.Atoi(value); err != nil || size <= 0 {
+			return t, fmt.Errorf("{size} tag needs a positive number; got %q", value)
+		}
+	case "font":
+		if value == "" {
+			return t, fmt.Errorf("{font} tag needs a font name")
+		}
+	default:
+		return t, fmt.Errorf("unknown tag {%s}", s)
+	}
+
+	return t, nil
+}
+
+// apply returns the style with the change of the tag
+func (t tag) apply(style Style) Style {
+	switch t.name {
+	case "b":
+		style.Bold = true
+	case "i":
+		style.Italic = true
+	case "color":
+		style.Color = t.value
+	case "size":
+		style.Size, _ = strconv.Atoi(t.value)
+	case "font":
+		style.Font = t.value
+	}
+
+	return style
+}
+
+// IsStyled checks if any part of the spans has a style
+func IsStyled(spans []Span) bool {
+	for _, span := range spans {
+		if span.Style != (Style{}) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+package markup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []Span
+	}{
+		{"plain text", []Span{{Text: "plain text"}}},
+		{"{b}bold{/} and {i}italic{/}", []Span{
+			{Text: "bold", Style: Style{Bold: true}},
+			{Text: " and "},
+			{Text: "italic", Style: Style{Italic: true}},
+		}},
+		{"{color=#f00}red {size=24}big{/} red{/}", []Span{
+			{Text: "red ", Style: Style{Color: "#f00"}},
+			{Text: "big", Style: Style{Color: "#f00", Size: 24}},
+			{Text: " red", Style: Style{Color: "#f00"}},
+		}},
+		{"{font=main}{b}x", []Span{{Text: "x", Style: Style{Bold: true, Font: "main"}}}},
+		{"{{b} {unknown} {size=big} {", []Span{{Text: "{b} {unknown} {size=big} {"}}},
+		{"extra{/}", []Span{{Text: "extra"}}},
+		{"wait{w=0.5} {b}here{/}", []Span{{Text: "wait"}, {Text: " "}, {Text: "here", Style: Style{Bold: true}}}},
+	}
+
+	for _, test := range tests {
+		if spans := Parse(test.text); !reflect.DeepEqual(spans, test.expected) {
+			t.Errorf("Parse(%q): expected %v; got: %v", test.text, test.expected, spans)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	// An escaped text inside tags must be one span with the style of the tags
+	text := "{b}" + Escape("a {/} b {{i} {") + "{/}"
+	expected := []Span{{Text: "a {/} b {{i} {", Style: Style{Bold: true}}}
+	if spans := Parse(text); !reflect.DeepEqual(spans, expected) {
+		t.Errorf("Parse(%q): expected %v; got: %v", text, expected, spans)
+	}
+}
+
+func TestPauses(t *testing.T) {
+	pauses := Pauses("Wait{w=0.5}... {{b} {b}now{/}{w=1}")
+	expected := []Pause{{At: 4, Duration: 500 * time.Millisecond}, {At: 13, Duration: time.Second}}
+
+	if !reflect.DeepEqual(pauses, expected) {
+		t.Errorf("pauses: expected %v; got: %v", expected, pauses)
+	}
+
+	if glyphs := Glyphs(Plain("Wait{w=0.5}... {{b} {b}now{/}{w=1}")); glyphs != 13 {
+		t.Errorf("glyphs: expected 13; got: %d", glyphs)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	problems := Check("{b}ok{/} {{escaped} {color=red} {size=0} {bold} {b=1} {w=-1} {i")
+	expected := []string{
+		"{color} tag: invalid hex color format: red",
+		`{size} tag needs a positive number; got "0"`,
+		"unknown tag {bold}",
+		"{b} tag doesn't have a value",
+		`{w} tag needs a positive number of seconds; got "-1"`,
+		"tag is not closed with }",
+	}
+
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("problems: expected %q; got: %q", expected, problems)
+	}
+}
+
+func TestLines(t *testing.T) {
+	bold := Style{Bold: true}
+	// Every character is 1 wide and bold characters are 2 wide
+	measure := func(s Span) int {
+		if s.Style.Bold {
+			return 2 * len(s.Text)
+		}
+		return len(s.Text)
+	}
+
+	lines := Lines(Parse("one {b}two{/}s three\nfour"), 12, measure)
+	expected := [][]Span{
+		{{Text: "one "}, {Text: "two", Style: bold}, {Text: "s"}},
+		{{Text: "three"}},
+		{{Text: "four"}},
+	}
+
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("lines: expected %v; got: %v", expected, lines)
+	}
+
+	if lines := Lines(Parse("a b\n\nc"), 0, measure); len(lines) != 3 || lines[1] != nil {
+		t.Errorf("lines without limit: expected 3 lines with an empty line; got: %v", lines)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	bold := Style{Bold: true}
+	lines := [][]Span{
+		{{Text: "one "}, {Text: "two", Style: bold}},
+		{{Text: "three"}},
+	}
+
+	expected := [][]Span{
+		{{Text: "one "}, {Text: "t", Style: bold}},
+		nil,
+	}
+	if truncated := Truncate(lines, 4); !reflect.DeepEqual(truncated, expected) {
+		t.Errorf("Truncate(4): expected %v; got: %v", expected, truncated)
+	}
+
+	if truncated := Truncate(lines, 11); !reflect.DeepEqual(truncated, lines) {
+		t.Errorf("Truncate(11): expected %v; got: %v", lines, truncated)
+	}
+}
@@ -0,0 +1,236 @@
+// particles package simulates particles on the CPU for weather and ambient effects like rain, snow and dust. Emitters spawn particles in their shape and move them with their velocity and gravity until their lifetime is over
+package particles
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// SHAPES are the shapes that emitters spawn particles in. A point spawns them at x and y, a line from x to x + w at y and a rect inside x, y, w and h
+var SHAPES = []string{"point", "line", "rect"}
+
+// LAYERS are the layers that particles are drawn in. Back is between the background and sprites and front is over sprites
+var LAYERS = []string{"back", "front"}
+
+// DEFAULT_MAX is the number of particles that an emitter can have at once without a max
+const DEFAULT_MAX = 1000
+
+// MAX_PARTICLES is the most particles that an emitter can have at once
+const MAX_PARTICLES = 10000
+
+// WARM_STEP is the step of the simulation when an emitter is warmed up
+const WARM_STEP = time.Second / 30
+
+// MAX_WARM is the longest time that an emitter is warmed up, so particles with long lifetimes don't stop the game
+const MAX_WARM = 15 * time.Second
+
+// Range is a random value between Min and Max
+type Range struct {
+	Min float64
+	Max float64
+}
+
+// Over is a value that changes from Start to End in the life of a particle
+type Over struct {
+	Start float64
+	End   float64
+}
+
+// Config is the settings of an emitter. Positions of the shape are from 0 to 1 of the scene, other lengths are pixels and angles are degrees. Direction 0 is to the right and 90 is down
+type Config struct {
+	// Name is used to replace or stop the emitter. Emitters without a name can't be stopped
+	Name  string
+	Shape string
+	X     float64
+	Y     float64
+	W     float64
+	H     float64
+	// Rate is the number of particles spawned in a second
+	Rate float64
+	Max  int
+	// Lifetime is in seconds
+	Lifetime  Range
+	Speed     Range
+	Direction Range
+	GravityX  float64
+	GravityY  float64
+	Rotation  Range
+	// Spin is the rotation speed in degrees per second
+	Spin  Range
+	Size  Over
+	Alpha Over
+	// Stretch is the height of a particle to its width
+	Stretch float64
+	// Align rotates particles to their direction of motion, like rain drops
+	Align   bool
+	Texture string
+	Color   string
+	Layer   string
+	// Warm runs the emitter for a lifetime when it's created, so the scene is full of particles from the first frame
+	Warm bool
+}
+
+// Particle is a particle of an emitter. Its position is in pixels of the scene
+type Particle struct {
+	X        float64
+	Y        float64
+	VX       float64
+	VY       float64
+	Rotation float64
+	Spin     float64
+	Age      float64
+	Life     float64
+}
+
+// Emitter spawns and moves the particles of its config
+type Emitter struct {
+	Config    Config
+	Particles []Particle
+	rng       *rand.Rand
+	// spawn is the part of the next particle that is spawned. Rates that are not whole numbers spawn a particle after a few updates
+	spawn   float64
+	stopped bool
+}
+
+// DefaultConfig returns the config of a dust-like emitter that fills the scene. Scripts change its fields
+func DefaultConfig() Config {
+	return Config{
+		Shape:     "rect",
+		W:         1,
+		H:         1,
+		Rate:      10,
+		Max:       DEFAULT_MAX,
+		Lifetime:  Range{2, 2},
+		Speed:     Range{20, 20},
+		Direction: Range{0, 360},
+		Size:      Over{4, 4},
+		Alpha:     Over{1, 1},
+		Stretch:   1,
+		Color:     "#ffffff",
+		Layer:     "front",
+	}
+}
+
+// NewEmitter returns an emitter of the config. rng chooses the random values of particles
+func NewEmitter(c Config, rng *rand.Rand) *Emitter {
+	return &Emitter{Config: c, rng: rng}
+}
+
+// Update spawns new particles and moves the particles for dt. w and h are the size of the scene
+func (e *Emitter) Update(dt time.Duration, w float64, h float64) {
+	s := dt.Seconds()
+
+	// Old particles are removed and moved before spawning, so new particles start at the emitter
+	alive := e.Particles[:0]
+	for _, p := range e.Particles {
+		p.Age += s
+		if p.Age >= p.Life {
+			continue
+		}
+
+		p.VX += e.Config.GravityX * s
+		p.VY += e.Config.GravityY * s
+		p.X += p.VX * s
+		p.Y += p.VY * s
+		p.Rotation += p.Spin * s
+
+		if e.Config.Align {
+			p.Rotation = math.Atan2(p.VY, p.VX)*180/math.Pi - 90
+		}
+
+		alive = append(alive, p)
+	}
+	e.Particles = alive
+
+	if e.stopped {
+		return
+	}
+
+	// Particles that can't be spawned because of max are dropped, so a high rate doesn't keep them for the next updates
+	e.spawn += e.Config.Rate * s
+	for ; e.spawn >= 1; e.spawn-- {
+		if len(e.Particles) >= min(e.Config.Max, MAX_PARTICLES) {
+			e.spawn = 0
+			break
+		}
+
+		e.Particles = append(e.Particles, e.newParticle(w, h))
+	}
+}
+
+// Warm runs the emitter for the longest lifetime of its particles, but not more than MAX_WARM
+func (e *Emitter) Warm(w float64, h float64) {
+	for t := time.Duration(0); t.Seconds() < e.Config.Lifetime.Max && t < MAX_WARM; t += WARM_STEP {
+		e.Update(WARM_STEP, w, h)
+	}
+}
+
+// Stop stops spawning new particles. The emitter is done when its particles are dead
+func (e *Emitter) Stop() {
+	e.stopped = true
+}
+
+// Stopped checks if the emitter doesn't spawn new particles
+func (e *Emitter) Stopped() bool {
+	return e.stopped
+}
+
+// Done checks if the emitter is stopped and all of its particles are dead
+func (e *Emitter) Done() bool {
+	return e.stopped && len(e.Particles) == 0
+}
+
+// Size returns the size of the particle in its age
+func (e *Emitter) Size(p *Particle) float64 {
+	return e.Config.Size.at(p.Age / p.Life)
+}
+
+// Alpha returns the opacity of the particle in its age
+func (e *Emitter) Alpha(p *Particle) float64 {
+	return min(max(e.Config.Alpha.at(p.Age/p.Life), 0), 1)
+}
+
+// newParticle returns a particle at a random point of the shape
+func (e *Emitter) newParticle(w float64, h float64) Particle {
+	c := &e.Config
+	x := c.X * w
+	y := c.Y * h
+
+	switch c.Shape {
+	case "line":
+		x += e.rng.Float64() * c.W * w
+	case "rect":
+		x += e.rng.Float64() * c.W * w
+		y += e.rng.Float64() * c.H * h
+	}
+
+	speed := e.random(c.Speed)
+	direction := e.random(c.Direction) * math.Pi / 180
+
+	p := Particle{
+		X:        x,
+		Y:        y,
+		VX:       math.Cos(direction) * speed,
+		VY:       math.Sin(direction) * speed,
+		Rotation: e.random(c.Rotation),
+		Spin:     e.random(c.Spin),
+		Life:     max(e.random(c.Lifetime), 0.001),
+	}
+
+	if c.Align {
+		p.Rotation = math.Atan2(p.VY, p.VX)*180/math.Pi - 90
+	}
+
+	return p
+}
+
+// random returns a random value of the range
+func (e *Emitter) random(r Range) float64 {
+	return r.Min + e.rng.Float64()*(r.Max-r.Min)
+}
+
+// at returns the value at the progress from 0 to 1
+func (o Over) at(progress float64) float64 {
+	return o.Start + (o.End-o.Start)*progress
+}
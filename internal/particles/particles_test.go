@@ -0,0 +1,103 @@
+package particles
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestEmitter(t *testing.T) {
+	c := DefaultConfig()
+	c.Shape = "point"
+	c.X, c.Y = 0.5, 0
+	c.Rate = 10
+	c.Lifetime = Range{1, 1}
+	c.Speed = Range{100, 100}
+	c.Direction = Range{90, 90}
+	c.GravityY = 100
+	c.Size = Over{4, 2}
+	c.Alpha = Over{1, 0}
+
+	e := NewEmitter(c, rand.New(rand.NewPCG(1, 2)))
+
+	e.Update(250*time.Millisecond, 200, 100)
+	if len(e.Particles) != 2 {
+		t.Fatalf("spawn: expected %v particles after 250ms; got: %v", 2, len(e.Particles))
+	}
+
+	p := e.Particles[0]
+	if p.X != 100 || p.Y != 0 || math.Abs(p.VY-100) > 1e-9 {
+		t.Errorf("new particle: expected to start at 100, 0 going down with 100px/s; got: %v, %v with %v", p.X, p.Y, p.VY)
+	}
+
+	e.Update(500*time.Millisecond, 200, 100)
+	p = e.Particles[0]
+	if math.Abs(p.VY-150) > 1e-9 || math.Abs(p.Y-75) > 1e-9 {
+		t.Errorf("gravity: expected speed 150 at 75; got: %v at %v", p.VY, p.Y)
+	}
+
+	if math.Abs(e.Size(&p)-3) > 1e-9 || math.Abs(e.Alpha(&p)-0.5) > 1e-9 {
+		t.Errorf("over life: expected size 3 and alpha 0.5 in the middle of life; got: %v and %v", e.Size(&p), e.Alpha(&p))
+	}
+
+	e.Stop()
+	e.Update(time.Second, 200, 100)
+	if !e.Done() {
+		t.Errorf("stop: expected no particles after their lifetime; got: %v", len(e.Particles))
+	}
+}
+
+func TestMax(t *testing.T) {
+	c := DefaultConfig()
+	c.Rate = 1000
+	c.Max = 5
+
+	e := NewEmitter(c, rand.New(rand.NewPCG(1, 2)))
+	e.Update(time.Second, 200, 100)
+
+	if len(e.Particles) != 5 {
+		t.Errorf("max: expected %v particles; got: %v", 5, len(e.Particles))
+	}
+
+	// Particles over max must not be spawned later
+	e.Config.Rate = 1e12
+	e.Update(time.Second, 200, 100)
+	if len(e.Particles) != 5 || e.spawn != 0 {
+		t.Errorf("high rate: expected %v particles and nothing left to spawn; got: %v and %v", 5, len(e.Particles), e.spawn)
+	}
+}
+
+func TestWarmLimit(t *testing.T) {
+	c := DefaultConfig()
+	c.Lifetime = Range{1e9, 1e9}
+	c.Speed = Range{0, 0}
+
+	e := NewEmitter(c, rand.New(rand.NewPCG(1, 2)))
+	e.Warm(200, 100)
+
+	// Every particle lives longer than the warm up, so there is one for every 1/10 second of MAX_WARM
+	expected := int(MAX_WARM.Seconds() * c.Rate)
+	if len(e.Particles) != expected {
+		t.Errorf("warm: expected %v particles; got: %v", expected, len(e.Particles))
+	}
+}
+
+func TestWeather(t *testing.T) {
+	for name := range WEATHERS {
+		c, ok := Weather(name, 1)
+		if !ok || c.Name != WEATHER_NAME || !c.Warm {
+			t.Errorf("%s: expected a warm emitter named %q; got: %+v", name, WEATHER_NAME, c)
+		}
+
+		e := NewEmitter(c, rand.New(rand.NewPCG(1, 2)))
+		e.Warm(1280, 720)
+		if len(e.Particles) == 0 {
+			t.Errorf("%s: expected particles after warming up", name)
+		}
+	}
+
+	if _, ok := Weather("fog", 1); ok {
+		t.Errorf("fog: expected not to be a weather")
+	}
+}
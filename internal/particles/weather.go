@@ -0,0 +1,95 @@
+package particles
+
+import (
+	"slices"
+	"strings"
+)
+
+// WEATHER_NAME is the name of the emitter of weather, so a new weather replaces the old one
+const WEATHER_NAME = "weather"
+
+// WEATHERS are the presets of weather. Intensity is from 0 to 1 and changes the number of particles
+var WEATHERS = map[string]func(intensity float64) Config{
+	"rain": func(intensity float64) Config {
+		c := DefaultConfig()
+		c.Shape = "line"
+		c.X, c.Y, c.W = -0.2, -0.05, 1.3
+		c.Rate = 400 * intensity
+		c.Lifetime = Range{0.8, 1.1}
+		c.Speed = Range{900, 1200}
+		c.Direction = Range{100, 104}
+		c.Size = Over{2, 2}
+		c.Alpha = Over{0.5, 0.5}
+		c.Stretch = 8
+		c.Align = true
+		c.Color = "#c8d2ff"
+
+		return c
+	},
+	"snow": func(intensity float64) Config {
+		c := DefaultConfig()
+		c.Shape = "line"
+		c.X, c.Y, c.W = -0.1, -0.05, 1.2
+		c.Rate = 60 * intensity
+		c.Lifetime = Range{8, 12}
+		c.Speed = Range{40, 90}
+		c.Direction = Range{70, 110}
+		c.Size = Over{6, 4}
+		c.Alpha = Over{0.9, 0.7}
+
+		return c
+	},
+	"petals": func(intensity float64) Config {
+		c := DefaultConfig()
+		c.Shape = "line"
+		c.X, c.Y, c.W = -0.2, -0.05, 1.2
+		c.Rate = 20 * intensity
+		c.Lifetime = Range{6, 9}
+		c.Speed = Range{60, 120}
+		c.Direction = Range{50, 90}
+		c.GravityY = 5
+		c.Rotation = Range{0, 360}
+		c.Spin = Range{-180, 180}
+		c.Size = Over{10, 10}
+		c.Stretch = 0.6
+		c.Color = "#ffb7c5"
+
+		return c
+	},
+	"dust": func(intensity float64) Config {
+		c := DefaultConfig()
+		c.Rate = 15 * intensity
+		c.Lifetime = Range{3, 6}
+		c.Speed = Range{5, 20}
+		c.Size = Over{3, 2}
+		c.Alpha = Over{0.6, 0}
+		c.Color = "#fff4d6"
+
+		return c
+	},
+}
+
+// Weather returns the config of the weather preset. The second return value is false if there isn't a weather with this name
+func Weather(name string, intensity float64) (Config, bool) {
+	preset, ok := WEATHERS[name]
+	if !ok {
+		return Config{}, false
+	}
+
+	c := preset(intensity)
+	c.Name = WEATHER_NAME
+	c.Warm = true
+
+	return c, true
+}
+
+// WeatherNames returns the names of the weather presets in alphabetical order
+func WeatherNames() string {
+	names := make([]string, 0, len(WEATHERS))
+	for name := range WEATHERS {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,164 @@
+// rtl package lays out right-to-left and bidirectional text. Text is wrapped in its logical order, Arabic letters are shaped and then every line is changed to the visual order, so it can be rendered from left to right by SDL_ttf
+package rtl
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// run is a part of a line with a single direction and its embedding level from the unicode bidi algorithm
+type run struct {
+	text  string
+	level int
+}
+
+// IsRTL checks if the first strong character of text is right-to-left. This is the direction of the paragraph in the unicode bidi algorithm
+func IsRTL(text string) bool {
+	for _, r := range text {
+		switch class(r) {
+		case bidi.L:
+			return false
+		case bidi.R, bidi.AL:
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasRTL checks if text has any right-to-left character. Other texts don't need a layout
+func HasRTL(text string) bool {
+	for _, r := range text {
+		if c := class(r); c == bidi.R || c == bidi.AL {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Layout wraps text to lines that are not wider than limit and returns them shaped and in visual order. A limit of 0 only breaks lines at new lines. measure returns the width of a text when it's rendered
+func Layout(text string, limit int, measure func(string) int) []string {
+	var lines []string
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		rtl := IsRTL(paragraph)
+
+		for _, line := range wrap(paragraph, limit, measure) {
+			lines = append(lines, Visual(Shape(line), rtl))
+		}
+	}
+
+	return lines
+}
+
+// Visual returns a line in visual order. If rtl is true, the line is a part of a right-to-left paragraph
+func Visual(line string, rtl bool) string {
+	var p bidi.Paragraph
+
+	var opts []bidi.Option
+	if rtl {
+		opts = append(opts, bidi.DefaultDirection(bidi.RightToLeft))
+	}
+
+	if _, err := p.SetString(line, opts...); err != nil {
+		return line
+	}
+
+	ordering, err := p.Order()
+	if err != nil {
+		return line
+	}
+
+	// x/text groups the line by direction but doesn't reorder it, so levels of the runs are found here. A left-to-right run without any strong character (like numbers) inside a left-to-right paragraph is a part of the right-to-left text around it
+	runs := make([]run, ordering.NumRuns())
+	maxLevel := 0
+	for i := range runs {
+		r := ordering.Run(i)
+		runs[i].text = r.String()
+
+		switch {
+		case r.Direction() == bidi.RightToLeft:
+			runs[i].level = 1
+		case rtl || !hasLTR(runs[i].text):
+			runs[i].level = 2
+		}
+
+		maxLevel = max(maxLevel, runs[i].level)
+	}
+
+	// From the highest level to the lowest odd level, every sequence of runs at that level or higher is reversed
+	for level := maxLevel; level >= 1; level-- {
+		for start := 0; start < len(runs); start++ {
+			if runs[start].level < level {
+				continue
+			}
+
+			end := start
+			for end+1 < len(runs) && runs[end+1].level >= level {
+				end++
+			}
+
+			for i, j := start, end; i < j; i, j = i+1, j-1 {
+				runs[i], runs[j] = runs[j], runs[i]
+			}
+			start = end
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range runs {
+		if r.level%2 == 1 {
+			b.WriteString(bidi.ReverseString(r.text))
+		} else {
+			b.WriteString(r.text)
+		}
+	}
+
+	return b.String()
+}
+
+// hasLTR checks if text has any left-to-right strong character
+func hasLTR(text string) bool {
+	for _, r := range text {
+		if class(r) == bidi.L {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wrap breaks a paragraph at spaces, so every line is not wider than limit. Words that are wider than limit get their own line
+func wrap(paragraph string, limit int, measure func(string) int) []string {
+	if limit <= 0 {
+		return []string{paragraph}
+	}
+
+	var lines []string
+	line := ""
+	for i, word := range strings.Split(paragraph, " ") {
+		if i == 0 {
+			line = word
+			continue
+		}
+
+		candidate := line + " " + word
+		if line != "" && measure(Shape(candidate)) > limit {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+
+		line = candidate
+	}
+
+	return append(lines, line)
+}
+
+func class(r rune) bidi.Class {
+	p, _ := bidi.LookupRune(r)
+
+	return p.Class()
+}
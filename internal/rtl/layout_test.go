@@ -0,0 +1,61 @@
+package rtl
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestShape(t *testing.T) {
+	tests := map[string]string{
+		"سلام":  "ﺳﻼﻡ",
+		"کتاب":  "ﮐﺘﺎﺏ",
+		"بَیت":  "ﺑَﯿﺖ",
+		"ب ب":   "ﺏ ﺏ",
+		"ـبـ":   "ـﺒـ",
+		"hello": "hello",
+	}
+
+	for text, expected := range tests {
+		if got := Shape(text); got != expected {
+			t.Errorf("Shape(%q): expected %+q; got: %+q", text, expected, got)
+		}
+	}
+}
+
+func TestVisual(t *testing.T) {
+	tests := []struct {
+		line     string
+		rtl      bool
+		expected string
+	}{
+		{"abc אבג", false, "abc גבא"},
+		{"אבג 123 דה", true, "הד 123 גבא"},
+		{"abc אבג 123", false, "abc 123 גבא"},
+		{"(אבג)", true, "(גבא)"},
+		{"אבג abc דה", true, "הד abc גבא"},
+	}
+
+	for _, test := range tests {
+		if got := Visual(test.line, test.rtl); got != test.expected {
+			t.Errorf("Visual(%q, %v): expected %q; got: %q", test.line, test.rtl, test.expected, got)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	// Every character is 1 pixel wide
+	measure := func(s string) int {
+		return utf8.RuneCountInString(s)
+	}
+
+	lines := Layout("אבג דה וז\nabc", 6, measure)
+	expected := []string{"הד גבא", "זו", "abc"}
+	if !slices.Equal(lines, expected) {
+		t.Errorf("lines: expected %q; got: %q", expected, lines)
+	}
+
+	if !IsRTL("123 אבג abc") || IsRTL("abc אבג") || HasRTL("abc 123") {
+		t.Errorf("direction of texts is not correct")
+	}
+}
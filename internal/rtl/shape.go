@@ -0,0 +1,183 @@
+package rtl
+
+// letter has the presentation forms of an Arabic letter. Letters in the Arabic Presentation Forms blocks have their forms in the order isolated, final, initial and medial, so only the first form is kept
+type letter struct {
+	isolated rune
+	// dual is true for letters that join to both sides. Other letters only join to the letter before them
+	dual bool
+}
+
+const (
+	TATWEEL = '\u0640'
+	// ZWJ is the zero width joiner. It joins letters like tatweel without a visible character
+	ZWJ = '\u200d'
+	LAM = 'ل'
+)
+
+// letters are the Arabic and Persian letters that have contextual forms
+var letters = map[rune]letter{
+	'ء': {0xFE80, false}, // hamza doesn't join, but it has an isolated form
+	'آ': {0xFE81, false},
+	'أ': {0xFE83, false},
+	'ؤ': {0xFE85, false},
+	'إ': {0xFE87, false},
+	'ئ': {0xFE89, true},
+	'ا': {0xFE8D, false},
+	'ب': {0xFE8F, true},
+	'ة': {0xFE93, false},
+	'ت': {0xFE95, true},
+	'ث': {0xFE99, true},
+	'ج': {0xFE9D, true},
+	'ح': {0xFEA1, true},
+	'خ': {0xFEA5, true},
+	'د': {0xFEA9, false},
+	'ذ': {0xFEAB, false},
+	'ر': {0xFEAD, false},
+	'ز': {0xFEAF, false},
+	'س': {0xFEB1, true},
+	'ش': {0xFEB5, true},
+	'ص': {0xFEB9, true},
+	'ض': {0xFEBD, true},
+	'ط': {0xFEC1, true},
+	'ظ': {0xFEC5, true},
+	'ع': {0xFEC9, true},
+	'غ': {0xFECD, true},
+	'ف': {0xFED1, true},
+	'ق': {0xFED5, true},
+	'ك': {0xFED9, true},
+	'ل': {0xFEDD, true},
+	'م': {0xFEE1, true},
+	'ن': {0xFEE5, true},
+	'ه': {0xFEE9, true},
+	'و': {0xFEED, false},
+	'ى': {0xFEEF, false},
+	'ي': {0xFEF1, true},
+	// Persian letters
+	'پ': {0xFB56, true},
+	'چ': {0xFB7A, true},
+	'ژ': {0xFB8A, false},
+	'ک': {0xFB8E, true},
+	'گ': {0xFB92, true},
+	'ی': {0xFBFC, true},
+}
+
+// lamAlef are the isolated forms of the ligatures of lam with alef letters
+var lamAlef = map[rune]rune{
+	'آ': 0xFEF5,
+	'أ': 0xFEF7,
+	'إ': 0xFEF9,
+	'ا': 0xFEFB,
+}
+
+// form indexes are added to the isolated form of a letter
+const (
+	ISOLATED = iota
+	FINAL
+	INITIAL
+	MEDIAL
+)
+
+// Shape changes the Arabic letters of text to the forms that join them to the letters around them. Fonts render the presentation forms without a shaping engine, so the letters don't look disconnected
+func Shape(text string) string {
+	runes := []rune(text)
+	shaped := make([]rune, 0, len(runes))
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		l, ok := letters[r]
+		if !ok || r == 'ء' {
+			if ok {
+				r = l.isolated
+			}
+			shaped = append(shaped, r)
+			continue
+		}
+
+		joinsBefore := joinsNext(runes, previous(runes, i))
+
+		// Lam and alef are written as one ligature
+		if r == LAM && i+1 < len(runes) {
+			if ligature, ok := lamAlef[runes[i+1]]; ok {
+				if joinsBefore {
+					ligature += FINAL
+				}
+				shaped = append(shaped, ligature)
+				i++
+				continue
+			}
+		}
+
+		joinsAfter := l.dual && joinsPrevious(runes, next(runes, i))
+
+		form := ISOLATED
+		switch {
+		case joinsBefore && joinsAfter:
+			form = MEDIAL
+		case joinsBefore:
+			form = FINAL
+		case joinsAfter:
+			form = INITIAL
+		}
+
+		shaped = append(shaped, l.isolated+rune(form))
+	}
+
+	return string(shaped)
+}
+
+// joinsNext checks if the rune at i can join to the letter after it
+func joinsNext(runes []rune, i int) bool {
+	if i < 0 {
+		return false
+	}
+
+	r := runes[i]
+	if r == TATWEEL || r == ZWJ {
+		return true
+	}
+
+	return letters[r].dual
+}
+
+// joinsPrevious checks if the rune at i can join to the letter before it
+func joinsPrevious(runes []rune, i int) bool {
+	if i < 0 {
+		return false
+	}
+
+	r := runes[i]
+	if r == TATWEEL || r == ZWJ {
+		return true
+	}
+
+	_, ok := letters[r]
+
+	return ok && r != 'ء'
+}
+
+// previous returns the index of the rune before i that isn't a diacritic, or -1
+func previous(runes []rune, i int) int {
+	for i--; i >= 0; i-- {
+		if !isTransparent(runes[i]) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// next returns the index of the rune after i that isn't a diacritic, or -1
+func next(runes []rune, i int) int {
+	for i++; i < len(runes); i++ {
+		if !isTransparent(runes[i]) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// isTransparent checks if r is an Arabic diacritic. Diacritics don't change the forms of the letters around them
+func isTransparent(r rune) bool {
+	return (r >= '\u064b' && r <= '\u065f') || r == '\u0670'
+}
@@ -0,0 +1,112 @@
+// save package reads and writes saved games. A save doesn't hold the story state itself, because a running lua coroutine can't be serialized. Instead it holds every step the player took, so the engine can replay the script up to the same position.
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Version is the version of the save file format. It must be increased whenever the format changes in a way that old saves can't be read anymore
+const Version = 1
+
+// ErrVersion is returned when a save file is written with another version of the save format
+var ErrVersion = errors.New("save file version is not supported")
+
+// Save struct is a model for data in a save file
+type Save struct {
+	Version     int       `json:"version"`
+	GameVersion string    `json:"gameVersion"`
+	Time        time.Time `json:"time"`
+	// Label is the name of the last label that the story jumped to. It's only used for showing the save to the player, because loading always replays the steps from the beginning
+	Label string `json:"label,omitempty"`
+	// Steps has one value for every time the script resumed. 0 means a normal resume and any other value is the option chosen by the player
+	Steps []int `json:"steps"`
+	// Inputs are the texts typed by the player for the input calls of the script in order
+	Inputs []string `json:"inputs,omitempty"`
+}
+
+// Dir returns the user data directory of the game and creates it if it doesn't exist
+func Dir(game string) (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(base, "fufu", slug(game))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// Write saves s in the slot file inside dir
+func Write(dir string, slot string, s *Save) error {
+	s.Version = Version
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash while saving doesn't corrupt the old save
+	path := slotPath(dir, slot)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// Read returns the save stored in the slot file inside dir
+func Read(dir string, slot string) (*Save, error) {
+	data, err := os.ReadFile(slotPath(dir, slot))
+	if err != nil {
+		return nil, err
+	}
+
+	s := Save{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	if s.Version != Version {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, s.Version)
+	}
+
+	return &s, nil
+}
+
+// Exists checks if there is a save in the slot file inside dir
+func Exists(dir string, slot string) bool {
+	_, err := os.Stat(slotPath(dir, slot))
+
+	return err == nil
+}
+
+func slotPath(dir string, slot string) string {
+	return filepath.Join(dir, slug(slot)+".json")
+}
+
+// slug changes a name to a string that is safe to be used as a file name
+func slug(name string) string {
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return unicode.ToLower(r)
+		}
+
+		return '-'
+	}, strings.TrimSpace(name))
+
+	if s == "" {
+		return "untitled"
+	}
+
+	return s
+}
@@ -0,0 +1,139 @@
+package save
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+
+	s := &Save{GameVersion: "1.0.0", Steps: []int{0, 0, 2, 0}}
+	if err := Write(dir, "quick", s); err != nil {
+		t.Fatalf("Write returned an error: %v", err)
+	}
+
+	if !Exists(dir, "quick") {
+		t.Errorf("Exists should return true after Write; got: %v", false)
+	}
+
+	got, err := Read(dir, "quick")
+	if err != nil {
+		t.Fatalf("Read returned an error: %v", err)
+	}
+
+	if got.Version != Version {
+		t.Errorf("save version expected %v; got: %v", Version, got.Version)
+	}
+
+	if !slices.Equal(got.Steps, s.Steps) {
+		t.Errorf("save steps expected %v; got: %v", s.Steps, got.Steps)
+	}
+}
+
+func TestReadVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "old.json"), []byte(`{"version": 0, "steps": [0]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Read(dir, "old"); !errors.Is(err, ErrVersion) {
+		t.Errorf("Read should return ErrVersion for other save versions; got: %v", err)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"My new game!": "my-new-game-",
+		"../quick":     "---quick",
+		"":             "untitled",
+	}
+
+	for name, expected := range tests {
+		if got := slug(name); got != expected {
+			t.Errorf("slug(%q) expected %q; got: %q", name, expected, got)
+		}
+	}
+}
+
+func TestSeen(t *testing.T) {
+	dir := t.TempDir()
+
+	seen, err := LoadSeen(dir)
+	if err != nil {
+		t.Fatalf("LoadSeen returned an error: %v", err)
+	}
+
+	id := LineID("main.lua", "Hello")
+	seen.Add(id)
+	if err := seen.Flush(); err != nil {
+		t.Fatalf("Flush returned an error: %v", err)
+	}
+
+	seen, err = LoadSeen(dir)
+	if err != nil {
+		t.Fatalf("LoadSeen returned an error: %v", err)
+	}
+
+	if !seen.Has(id) {
+		t.Errorf("line should be read after loading the seen file again; got: %v", false)
+	}
+
+	if seen.Has(LineID("main.lua", "Bye")) {
+		t.Errorf("line that is not added should not be read; got: %v", true)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	// The settings directory doesn't exist before the first write
+	dir := filepath.Join(t.TempDir(), "game")
+
+	s, err := LoadSettings(dir, Settings{SkipMode: SKIP_READ, TextSpeed: 40})
+	if err != nil {
+		t.Fatalf("LoadSettings returned an error: %v", err)
+	}
+
+	s.ToggleSkipMode()
+	if s.SkipMode != SKIP_ALL {
+		t.Errorf("skip mode expected %v; got: %v", SKIP_ALL, s.SkipMode)
+	}
+
+	if err := s.Write(); err != nil {
+		t.Fatalf("Write returned an error: %v", err)
+	}
+
+	got, err := LoadSettings(dir, Settings{SkipMode: SKIP_READ, TextSpeed: 40})
+	if err != nil {
+		t.Fatalf("LoadSettings returned an error: %v", err)
+	}
+
+	if got.SkipMode != SKIP_ALL || got.TextSpeed != 40 {
+		t.Errorf("settings expected %v and %v; got: %v and %v", SKIP_ALL, 40, got.SkipMode, got.TextSpeed)
+	}
+}
+
+func TestChangeTextSpeed(t *testing.T) {
+	s := Settings{TextSpeed: 15}
+
+	tests := []struct {
+		steps    int
+		expected int
+	}{
+		{1, 25},
+		{-2, 5},
+		{-1, 0},
+		{100, MAX_TEXT_SPEED},
+	}
+
+	for _, test := range tests {
+		s.ChangeTextSpeed(test.steps)
+		if s.TextSpeed != test.expected {
+			t.Errorf("%d steps: text speed expected %v; got: %v", test.steps, test.expected, s.TextSpeed)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package save
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Seen keeps the ids of all lines the player has read in any playthrough of the game
+type Seen struct {
+	path  string
+	ids   map[string]bool
+	dirty bool
+}
+
+// LineID returns a stable id for a line of the script. It's based on the script file and the text, so the id doesn't change when lines are added or removed around it
+func LineID(file string, text string) string {
+	sum := sha1.Sum([]byte(file + "\x00" + text))
+
+	return file + ":" + hex.EncodeToString(sum[:8])
+}
+
+// LoadSeen reads the seen lines file inside dir. If the file doesn't exist, an empty store is returned
+func LoadSeen(dir string) (*Seen, error) {
+	s := Seen{
+		path: filepath.Join(dir, "seen.json"),
+		ids:  make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		s.ids[id] = true
+	}
+
+	return &s, nil
+}
+
+// Add marks a line as read
+func (s *Seen) Add(id string) {
+	if !s.ids[id] {
+		s.ids[id] = true
+		s.dirty = true
+	}
+}
+
+// Has checks if the line is read before
+func (s *Seen) Has(id string) bool {
+	return s.ids[id]
+}
+
+// Flush writes the seen lines to the file if there is any new line
+func (s *Seen) Flush() error {
+	if !s.dirty {
+		return nil
+	}
+
+	ids := make([]string, 0, len(s.ids))
+	for id := range s.ids {
+		ids = append(ids, id)
+	}
+
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return err
+	}
+
+	s.dirty = false
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// SKIP_READ only skips the lines that the player has read before
+	SKIP_READ = "read"
+	// SKIP_ALL skips all lines until the next choice
+	SKIP_ALL = "all"
+)
+
+// TEXT_SPEED_STEP is the change of text speed when the player makes it faster or slower
+const TEXT_SPEED_STEP = 10
+
+// MAX_TEXT_SPEED is the fastest text speed that the player can choose
+const MAX_TEXT_SPEED = 200
+
+// Settings struct is a model for the player preferences. Unlike config.json, these are changed by the player and stored in the user data directory
+type Settings struct {
+	path     string
+	SkipMode string `json:"skipMode"`
+	// Language is the string table that translates the script. Empty string shows the texts of the script itself
+	Language string `json:"language"`
+	// TextSpeed is the number of characters shown in a second by the typewriter. 0 shows the whole text at once
+	TextSpeed int `json:"textSpeed"`
+}
+
+// LoadSettings reads the settings file inside dir. Fields that don't exist in the file keep the values of defaults
+func LoadSettings(dir string, defaults Settings) (*Settings, error) {
+	s := defaults
+	s.path = filepath.Join(dir, "settings.json")
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	if s.SkipMode != SKIP_READ && s.SkipMode != SKIP_ALL {
+		s.SkipMode = defaults.SkipMode
+	}
+
+	if s.TextSpeed < 0 {
+		s.TextSpeed = defaults.TextSpeed
+	}
+
+	return &s, nil
+}
+
+// ToggleSkipMode changes the skip mode between skipping read lines and skipping all lines
+func (s *Settings) ToggleSkipMode() {
+	if s.SkipMode == SKIP_ALL {
+		s.SkipMode = SKIP_READ
+	} else {
+		s.SkipMode = SKIP_ALL
+	}
+}
+
+// ChangeTextSpeed adds steps of TEXT_SPEED_STEP to the text speed. The speed stays from 0 to MAX_TEXT_SPEED
+func (s *Settings) ChangeTextSpeed(steps int) {
+	s.TextSpeed = min(max(s.TextSpeed+steps*TEXT_SPEED_STEP, 0), MAX_TEXT_SPEED)
+}
+
+// Write stores the settings in the settings file. The directory of the file is created if it doesn't exist
+func (s *Settings) Write() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0o644)
+}
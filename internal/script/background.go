@@ -0,0 +1,54 @@
+package script
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ORIGINS_X are the horizontal origins of a background. The origin decides which part of a background that is bigger than the scene is shown
+var ORIGINS_X = []string{"left", "center", "right"}
+
+// ORIGINS_Y are the vertical origins of a background
+var ORIGINS_Y = []string{"top", "center", "bottom"}
+
+// Origin is the origin of a background shown with bg
+type Origin struct {
+	X string
+	Y string
+}
+
+// ReadOrigin reads originx and originy of the bg properties. Origins that are not set or not valid are left and top
+func ReadOrigin(properties *lua.LTable) (*Origin, []string) {
+	o := Origin{X: ORIGINS_X[0], Y: ORIGINS_Y[0]}
+	var problems []string
+
+	if properties == nil {
+		return &o, nil
+	}
+
+	for _, field := range []struct {
+		name   string
+		v      *string
+		values []string
+	}{
+		{"originx", &o.X, ORIGINS_X},
+		{"originy", &o.Y, ORIGINS_Y},
+	} {
+		v := properties.RawGetString(field.name)
+		if v == lua.LNil {
+			continue
+		}
+
+		if s, ok := v.(lua.LString); ok && slices.Contains(field.values, string(s)) {
+			*field.v = string(s)
+			continue
+		}
+
+		problems = append(problems, fmt.Sprintf("%s must be one of %s; got %s", field.name, strings.Join(field.values, ", "), v.String()))
+	}
+
+	return &o, problems
+}
@@ -0,0 +1,31 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReadOrigin(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	properties := L.NewTable()
+	properties.RawSetString("originy", lua.LString("bottom"))
+
+	// originy must not change the horizontal origin
+	o, problems := ReadOrigin(properties)
+	if *o != (Origin{X: "left", Y: "bottom"}) || problems != nil {
+		t.Errorf("originy: expected %+v; got: %+v and %q", Origin{X: "left", Y: "bottom"}, *o, problems)
+	}
+
+	properties.RawSetString("originx", lua.LString("right"))
+	properties.RawSetString("originy", lua.LString("left"))
+
+	o, problems = ReadOrigin(properties)
+	expectedProblems := []string{"originy must be one of top, center, bottom; got left"}
+	if *o != (Origin{X: "right", Y: "top"}) || !reflect.DeepEqual(problems, expectedProblems) {
+		t.Errorf("originx: expected %+v and %q; got: %+v and %q", Origin{X: "right", Y: "top"}, expectedProblems, *o, problems)
+	}
+}
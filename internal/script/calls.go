@@ -0,0 +1,154 @@
+package script
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/yuin/gopher-lua/ast"
+)
+
+// Call is a call of a global function in a script file, found without running the script
+type Call struct {
+	Name string
+	File string
+	Line int
+	Args []ast.Expr
+}
+
+// FindCalls returns the calls of the global functions in names inside a script file in the order they are written
+func (l *Loader) FindCalls(file string, names ...string) ([]Call, error) {
+	file = path.Clean(filepath.ToSlash(file))
+
+	chunk, err := l.parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []Call
+	walkStmts(chunk, func(call *ast.FuncCallExpr) {
+		ident, ok := call.Func.(*ast.IdentExpr)
+		if !ok || call.Receiver != nil || !slices.Contains(names, ident.Value) {
+			return
+		}
+
+		calls = append(calls, Call{
+			Name: ident.Value,
+			File: file,
+			Line: call.Line(),
+			Args: call.Args,
+		})
+	})
+
+	return calls, nil
+}
+
+// walkStmts calls visit for every function call inside the statements
+func walkStmts(stmts []ast.Stmt, visit func(*ast.FuncCallExpr)) {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			walkExprs(s.Lhs, visit)
+			walkExprs(s.Rhs, visit)
+		case *ast.LocalAssignStmt:
+			walkExprs(s.Exprs, visit)
+		case *ast.FuncCallStmt:
+			walkExpr(s.Expr, visit)
+		case *ast.DoBlockStmt:
+			walkStmts(s.Stmts, visit)
+		case *ast.WhileStmt:
+			walkExpr(s.Condition, visit)
+			walkStmts(s.Stmts, visit)
+		case *ast.RepeatStmt:
+			walkStmts(s.Stmts, visit)
+			walkExpr(s.Condition, visit)
+		case *ast.IfStmt:
+			walkExpr(s.Condition, visit)
+			walkStmts(s.Then, visit)
+			walkStmts(s.Else, visit)
+		case *ast.NumberForStmt:
+			walkExprs([]ast.Expr{s.Init, s.Limit, s.Step}, visit)
+			walkStmts(s.Stmts, visit)
+		case *ast.GenericForStmt:
+			walkExprs(s.Exprs, visit)
+			walkStmts(s.Stmts, visit)
+		case *ast.FuncDefStmt:
+			walkExpr(s.Func, visit)
+		case *ast.ReturnStmt:
+			walkExprs(s.Exprs, visit)
+		}
+	}
+}
+
+func walkExprs(exprs []ast.Expr, visit func(*ast.FuncCallExpr)) {
+	for _, expr := range exprs {
+		walkExpr(expr, visit)
+	}
+}
+
+// walkExpr calls visit for the expression and every function call inside it
+func walkExpr(expr ast.Expr, visit func(*ast.FuncCallExpr)) {
+	switch e := expr.(type) {
+	case *ast.FuncCallExpr:
+		visit(e)
+		walkExprs([]ast.Expr{e.Func, e.Receiver}, visit)
+		walkExprs(e.Args, visit)
+	case *ast.AttrGetExpr:
+		walkExprs([]ast.Expr{e.Object, e.Key}, visit)
+	case *ast.TableExpr:
+		for _, field := range e.Fields {
+			walkExprs([]ast.Expr{field.Key, field.Value}, visit)
+		}
+	case *ast.LogicalOpExpr:
+		walkExprs([]ast.Expr{e.Lhs, e.Rhs}, visit)
+	case *ast.RelationalOpExpr:
+		walkExprs([]ast.Expr{e.Lhs, e.Rhs}, visit)
+	case *ast.StringConcatOpExpr:
+		walkExprs([]ast.Expr{e.Lhs, e.Rhs}, visit)
+	case *ast.ArithmeticOpExpr:
+		walkExprs([]ast.Expr{e.Lhs, e.Rhs}, visit)
+	case *ast.UnaryMinusOpExpr:
+		walkExpr(e.Expr, visit)
+	case *ast.UnaryNotOpExpr:
+		walkExpr(e.Expr, visit)
+	case *ast.UnaryLenOpExpr:
+		walkExpr(e.Expr, visit)
+	case *ast.FunctionExpr:
+		walkStmts(e.Stmts, visit)
+	}
+}
+
+// FindFiles returns the file and all files that it loads with require and include. Only calls with a constant string are followed, because other paths are known only when the script runs
+func (l *Loader) FindFiles(file string) ([]string, error) {
+	files := []string{path.Clean(filepath.ToSlash(file))}
+
+	for i := 0; i < len(files); i++ {
+		calls, err := l.FindCalls(files[i], "require", "include")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, call := range calls {
+			if len(call.Args) == 0 {
+				continue
+			}
+
+			name, ok := call.Args[0].(*ast.StringExpr)
+			if !ok {
+				continue
+			}
+
+			f := name.Value
+			if call.Name == "require" {
+				f = moduleFile(f)
+			}
+
+			f = path.Clean(f)
+			if !slices.Contains(files, f) {
+				files = append(files, f)
+			}
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,136 @@
+package script
+
+import (
+	"fmt"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Shake shakes the scene by intensity pixels. The shake gets weaker until it stops at the end of its duration
+type Shake struct {
+	Intensity float64
+	Duration  time.Duration
+}
+
+// Zoom scales the scene by its factor around the focus. It's a call like zoom(2, 1000, {focus = alice, easing = "ease_out"})
+type Zoom struct {
+	Factor   float64
+	Duration time.Duration
+	Easing   string
+	// FocusX and FocusY are the point of the scene from 0 to 1 that stays in its place. If Character is set, the focus is the center of its sprite
+	FocusX    float64
+	FocusY    float64
+	Character string
+}
+
+// Pan moves the camera by pixels from its place, so pan(0, 0, ms) moves it back
+type Pan struct {
+	X        float64
+	Y        float64
+	Duration time.Duration
+	Easing   string
+}
+
+// ReadShake reads the arguments of shake
+func ReadShake(intensity lua.LValue, duration lua.LValue) (*Shake, []string) {
+	var problems []string
+	s := Shake{}
+
+	if i, ok := intensity.(lua.LNumber); !ok || i < 0 {
+		problems = append(problems, fmt.Sprintf("intensity of shake must be a number of pixels that is not negative; got %s", intensity.String()))
+	} else {
+		s.Intensity = float64(i)
+	}
+
+	d, dp := readDuration("duration of shake", duration)
+	s.Duration = d
+
+	return &s, append(problems, dp...)
+}
+
+// ReadZoom reads the arguments of zoom. The focus is the center of the scene if it's not set
+func ReadZoom(factor lua.LValue, duration lua.LValue, properties *lua.LTable) (*Zoom, []string) {
+	var problems []string
+	z := Zoom{Factor: 1, Easing: "linear", FocusX: 0.5, FocusY: 0.5}
+
+	if f, ok := factor.(lua.LNumber); !ok || f <= 0 {
+		problems = append(problems, fmt.Sprintf("factor of zoom must be a number bigger than 0; got %s", factor.String()))
+	} else {
+		z.Factor = float64(f)
+	}
+
+	d, dp := readDuration("duration of zoom", duration)
+	problems = append(problems, dp...)
+	z.Duration = d
+
+	if properties == nil {
+		return &z, problems
+	}
+
+	e, ep := readEasing("easing of zoom", properties.RawGetString("easing"))
+	problems = append(problems, ep...)
+	z.Easing = e
+
+	switch focus := properties.RawGetString("focus").(type) {
+	case *lua.LTable:
+		// A table with a name is a character and other tables are points of the scene
+		if focus.RawGetString("name") != lua.LNil {
+			c, cp := ReadCharacter(focus)
+			problems = append(problems, cp...)
+			z.Character = c.Name
+			break
+		}
+
+		for _, field := range []string{"x", "y"} {
+			v, ok := focus.RawGetString(field).(lua.LNumber)
+			if !ok || v < 0 || v > 1 {
+				problems = append(problems, fmt.Sprintf("%s of zoom focus must be a number from 0 to 1; got %s", field, focus.RawGetString(field).String()))
+				continue
+			}
+
+			if field == "x" {
+				z.FocusX = float64(v)
+			} else {
+				z.FocusY = float64(v)
+			}
+		}
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("focus of zoom must be a character or a table like {x = 0.5, y = 0.5}; got %s", focus.Type()))
+	}
+
+	return &z, problems
+}
+
+// ReadPan reads the arguments of pan
+func ReadPan(x lua.LValue, y lua.LValue, duration lua.LValue, properties *lua.LTable) (*Pan, []string) {
+	var problems []string
+	p := Pan{Easing: "linear"}
+
+	for i, v := range []lua.LValue{x, y} {
+		n, ok := v.(lua.LNumber)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s of pan must be a number of pixels; got %s", []string{"x", "y"}[i], v.Type()))
+			continue
+		}
+
+		if i == 0 {
+			p.X = float64(n)
+		} else {
+			p.Y = float64(n)
+		}
+	}
+
+	d, dp := readDuration("duration of pan", duration)
+	problems = append(problems, dp...)
+	p.Duration = d
+
+	if properties != nil {
+		e, ep := readEasing("easing of pan", properties.RawGetString("easing"))
+		problems = append(problems, ep...)
+		p.Easing = e
+	}
+
+	return &p, problems
+}
@@ -0,0 +1,83 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReadShake(t *testing.T) {
+	s, problems := ReadShake(lua.LNumber(8), lua.LNumber(300))
+	if !reflect.DeepEqual(s, &Shake{Intensity: 8, Duration: 300 * time.Millisecond}) || problems != nil {
+		t.Errorf("shake(8, 300): expected intensity 8 for 300ms; got: %+v and %q", s, problems)
+	}
+
+	_, problems = ReadShake(lua.LNumber(-1), lua.LNil)
+	expected := []string{
+		"intensity of shake must be a number of pixels that is not negative; got -1",
+		"duration of shake must be a number of milliseconds; got nil",
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("shake(-1): expected problems %q; got: %q", expected, problems)
+	}
+}
+
+func TestReadZoom(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	tests := []struct {
+		args     string
+		expected *Zoom
+		problems []string
+	}{
+		{`2, 1000`, &Zoom{Factor: 2, Duration: time.Second, Easing: "linear", FocusX: 0.5, FocusY: 0.5}, nil},
+		{`1.5, 500, {focus = {x = 0.2, y = 0.3}, easing = "ease_out"}`, &Zoom{Factor: 1.5, Duration: 500 * time.Millisecond, Easing: "ease_out", FocusX: 0.2, FocusY: 0.3}, nil},
+		{`2, 0, {focus = {name = "Alice"}}`, &Zoom{Factor: 2, Easing: "linear", FocusX: 0.5, FocusY: 0.5, Character: "Alice"}, nil},
+		{`0, 100, {focus = {x = 2}}`, &Zoom{Factor: 1, Duration: 100 * time.Millisecond, Easing: "linear", FocusX: 0.5, FocusY: 0.5}, []string{
+			"factor of zoom must be a number bigger than 0; got 0",
+			"x of zoom focus must be a number from 0 to 1; got 2",
+			"y of zoom focus must be a number from 0 to 1; got nil",
+		}},
+		{`2, 100, {focus = "left"}`, &Zoom{Factor: 2, Duration: 100 * time.Millisecond, Easing: "linear", FocusX: 0.5, FocusY: 0.5}, []string{
+			"focus of zoom must be a character or a table like {x = 0.5, y = 0.5}; got string",
+		}},
+	}
+
+	for _, test := range tests {
+		if err := L.DoString("args = {" + test.args + "}"); err != nil {
+			t.Fatal(err)
+		}
+
+		args := L.GetGlobal("args").(*lua.LTable)
+		properties, _ := args.RawGetInt(3).(*lua.LTable)
+		z, problems := ReadZoom(args.RawGetInt(1), args.RawGetInt(2), properties)
+		if !reflect.DeepEqual(z, test.expected) {
+			t.Errorf("%s: expected %+v; got: %+v", test.args, test.expected, z)
+		}
+
+		if !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("%s: expected problems %q; got: %q", test.args, test.problems, problems)
+		}
+	}
+}
+
+func TestReadPan(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	properties := L.NewTable()
+	properties.RawSetString("easing", lua.LString("ease_in_out"))
+	p, problems := ReadPan(lua.LNumber(-120), lua.LNumber(40), lua.LNumber(800), properties)
+	if !reflect.DeepEqual(p, &Pan{X: -120, Y: 40, Duration: 800 * time.Millisecond, Easing: "ease_in_out"}) || problems != nil {
+		t.Errorf("pan(-120, 40, 800): expected the camera to move in 800ms; got: %+v and %q", p, problems)
+	}
+
+	_, problems = ReadPan(lua.LString("left"), lua.LNumber(0), lua.LNumber(100), nil)
+	expected := []string{"x of pan must be a number of pixels; got string"}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("pan(\"left\"): expected problems %q; got: %q", expected, problems)
+	}
+}
@@ -0,0 +1,156 @@
+package script
+
+import (
+	"fmt"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Option is an option of a choice. Options are strings or tables like {text = "Open the door", condition = has_key, enabled = true, tooltip = "..."}
+type Option struct {
+	Text string
+	// Index is the position of the option in the options table. choice returns it when the option is chosen
+	Index int
+	// Visible is false if the condition of the option is false. Hidden options are not shown at all
+	Visible bool
+	// Enabled is false for options that are shown but can't be chosen
+	Enabled bool
+	Tooltip string
+}
+
+// Choice is a call of the choice function with its options and properties
+type Choice struct {
+	Options []Option
+	// Timeout chooses Default after it ends. It's 0 for choices without a time limit
+	Timeout time.Duration
+	Default int
+}
+
+// ReadChoice reads the options and properties of a choice. Conditions of the options are called here, so they see the state of the story when the choice is shown. Options that are not valid are skipped and returned as problems
+func ReadChoice(L *lua.LState, options *lua.LTable, properties *lua.LTable) (*Choice, []string) {
+	c := Choice{}
+	var problems []string
+
+	if options == nil {
+		return &c, []string{"options must be a table"}
+	}
+
+	for i := 1; i <= options.Len(); i++ {
+		switch v := options.RawGetInt(i).(type) {
+		case lua.LString:
+			c.Options = append(c.Options, Option{Text: string(v), Index: i, Visible: true, Enabled: true})
+		case *lua.LTable:
+			text, ok := v.RawGetString("text").(lua.LString)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("option %d must have a string text", i))
+				continue
+			}
+
+			option := Option{Text: string(text), Index: i}
+			var problem string
+			if option.Visible, problem = readCondition(L, v, "condition"); problem != "" {
+				problems = append(problems, fmt.Sprintf("condition of option %d %s", i, problem))
+			}
+			if option.Enabled, problem = readCondition(L, v, "enabled"); problem != "" {
+				problems = append(problems, fmt.Sprintf("enabled of option %d %s", i, problem))
+			}
+
+			switch tooltip := v.RawGetString("tooltip").(type) {
+			case lua.LString:
+				option.Tooltip = string(tooltip)
+			case *lua.LNilType:
+			default:
+				problems = append(problems, fmt.Sprintf("tooltip of option %d must be a string; got %s", i, tooltip.Type()))
+			}
+
+			c.Options = append(c.Options, option)
+		default:
+			problems = append(problems, fmt.Sprintf("option %d must be a string or a table; got %s", i, v.Type()))
+		}
+	}
+
+	available := c.Available()
+	if options.Len() == 0 {
+		problems = append(problems, "there are no options")
+	} else if len(c.Options) > 0 && len(available) == 0 {
+		problems = append(problems, "there are no options that can be chosen")
+	}
+
+	if properties == nil {
+		return &c, problems
+	}
+
+	switch timeout := properties.RawGetString("timeout").(type) {
+	case lua.LNumber:
+		if timeout <= 0 {
+			problems = append(problems, fmt.Sprintf("timeout must be a positive number of seconds; got %v", timeout))
+			break
+		}
+		c.Timeout = time.Duration(float64(timeout) * float64(time.Second))
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("timeout must be a number; got %s", timeout.Type()))
+	}
+
+	switch def := properties.RawGetString("default").(type) {
+	case lua.LNumber:
+		c.Default = int(def)
+		if !c.CanChoose(c.Default) {
+			problems = append(problems, fmt.Sprintf("default option %d can't be chosen", c.Default))
+			c.Default = 0
+		}
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("default must be a number; got %s", def.Type()))
+	}
+
+	// Timed choices without a default choose the first option that can be chosen
+	if c.Timeout > 0 && c.Default == 0 && len(available) > 0 {
+		c.Default = available[0]
+	}
+
+	return &c, problems
+}
+
+// Available returns the indexes of the options that can be chosen
+func (c *Choice) Available() []int {
+	var indexes []int
+	for _, option := range c.Options {
+		if option.Visible && option.Enabled {
+			indexes = append(indexes, option.Index)
+		}
+	}
+
+	return indexes
+}
+
+// CanChoose checks if the option with the index can be chosen
+func (c *Choice) CanChoose(index int) bool {
+	for _, option := range c.Options {
+		if option.Index == index {
+			return option.Visible && option.Enabled
+		}
+	}
+
+	return false
+}
+
+// readCondition returns a boolean field of an option. The field can be a function that returns the value. Options without the field are true. If the field has another type, the problem is returned
+func readCondition(L *lua.LState, option *lua.LTable, field string) (bool, string) {
+	switch v := option.RawGetString(field).(type) {
+	case *lua.LNilType:
+		return true, ""
+	case lua.LBool:
+		return bool(v), ""
+	case *lua.LFunction:
+		// Errors of the function are errors of the script, so they are not protected
+		L.CallByParam(lua.P{Fn: v, NRet: 1})
+		result := L.Get(-1)
+		L.Pop(1)
+
+		return lua.LVAsBool(result), ""
+	default:
+		return true, "must be a boolean or a function; got " + v.Type().String()
+	}
+}
@@ -0,0 +1,85 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// readTestChoice runs the script and reads the choice from the options and properties globals
+func readTestChoice(t *testing.T, script string) (*Choice, []string) {
+	t.Helper()
+
+	L := lua.NewState()
+	t.Cleanup(L.Close)
+
+	if err := L.DoString(script); err != nil {
+		t.Fatalf("failed to run the script: %v", err)
+	}
+
+	options, _ := L.GetGlobal("options").(*lua.LTable)
+	properties, _ := L.GetGlobal("properties").(*lua.LTable)
+
+	return ReadChoice(L, options, properties)
+}
+
+func TestReadChoice(t *testing.T) {
+	c, problems := readTestChoice(t, `
+local has_key = false
+options = {
+	"Wait",
+	{text = "Open the door", condition = function() return has_key end},
+	{text = "Fly", enabled = false, tooltip = "You don't have wings"},
+	{text = "Run"},
+}
+properties = {timeout = 2.5}
+`)
+
+	if len(problems) > 0 {
+		t.Fatalf("expected no problems; got: %q", problems)
+	}
+
+	expected := []Option{
+		{Text: "Wait", Index: 1, Visible: true, Enabled: true},
+		{Text: "Open the door", Index: 2, Visible: false, Enabled: true},
+		{Text: "Fly", Index: 3, Visible: true, Enabled: false, Tooltip: "You don't have wings"},
+		{Text: "Run", Index: 4, Visible: true, Enabled: true},
+	}
+	if !reflect.DeepEqual(c.Options, expected) {
+		t.Errorf("options: expected %v; got: %v", expected, c.Options)
+	}
+
+	if available := c.Available(); !reflect.DeepEqual(available, []int{1, 4}) {
+		t.Errorf("available options: expected [1 4]; got: %v", available)
+	}
+
+	if c.Timeout != 2500*time.Millisecond || c.Default != 1 {
+		t.Errorf("timeout: expected 2.5s with default 1; got: %v with default %d", c.Timeout, c.Default)
+	}
+}
+
+func TestReadChoiceProblems(t *testing.T) {
+	_, problems := readTestChoice(t, `
+options = {{text = "Locked", enabled = false}, 2, {condition = true}, {text = "Maybe", condition = "yes", tooltip = 1}}
+properties = {timeout = "soon", default = 1}
+`)
+
+	expected := []string{
+		"option 2 must be a string or a table; got number",
+		"option 3 must have a string text",
+		"condition of option 4 must be a boolean or a function; got string",
+		"tooltip of option 4 must be a string; got number",
+		"timeout must be a number; got string",
+		"default option 1 can't be chosen",
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("problems: expected %q; got: %q", expected, problems)
+	}
+
+	_, problems = readTestChoice(t, `options = {{text = "Locked", enabled = false}}`)
+	if !reflect.DeepEqual(problems, []string{"there are no options that can be chosen"}) {
+		t.Errorf("problems of a choice without available options: got: %q", problems)
+	}
+}
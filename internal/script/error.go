@@ -0,0 +1,48 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// Error is an error in a script file with the place that it happened
+type Error struct {
+	File    string
+	Line    int
+	Message string
+	// Traceback is the lua stack traceback of runtime errors
+	Traceback string
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return e.Message
+	}
+
+	if e.Line <= 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// syntaxError changes the errors of the lua parser to Error, because they don't have the same format as runtime errors
+func syntaxError(file string, err error) error {
+	var parseErr *parse.Error
+	if !errors.As(err, &parseErr) {
+		return &Error{File: file, Message: err.Error()}
+	}
+
+	line := parseErr.Pos.Line
+	message := parseErr.Message
+	if line == parse.EOF {
+		line = 0
+		message += " at the end of the file"
+	} else if parseErr.Token != "" {
+		message += fmt.Sprintf(" near '%s'", parseErr.Token)
+	}
+
+	return &Error{File: file, Line: line, Message: message}
+}
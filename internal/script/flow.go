@@ -0,0 +1,187 @@
+// script package contains the parts of the lua environment that are not related to drawing and playing the story, so they can be used without a window
+package script
+
+import (
+	"errors"
+	"sort"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Flow keeps the labels of the story. Labels are functions that can be reached by their names with jump and call
+type Flow struct {
+	labels map[string]*lua.LFunction
+	// Current is the name of the last label that the story jumped to
+	Current string
+}
+
+// jump is the value raised as an error by the jump function. Runner catches it and starts the label in a new coroutine
+type jump struct {
+	label string
+}
+
+// flowFunctions defines call in lua. A label that is called must be a lua call, because the script can't yield inside a function called from go. pcall, xpcall, coroutine.resume and coroutine.wrap are wrapped to raise the error of jump again, so a jump inside them isn't caught like other errors
+const flowFunctions = `
+local find, is_jump = ...
+local pcall, xpcall, error = pcall, xpcall, error
+local create, resume = coroutine.create, coroutine.resume
+
+function call(name, ...)
+	return find(name)(...)
+end
+
+local function rethrow(ok, ...)
+	if not ok and is_jump((...)) then
+		error((...), 0)
+	end
+
+	return ok, ...
+end
+
+function _G.pcall(fn, ...)
+	return rethrow(pcall(fn, ...))
+end
+
+function _G.xpcall(fn, handler)
+	return rethrow(xpcall(fn, function(err)
+		if is_jump(err) then
+			return err
+		end
+
+		return handler(err)
+	end))
+end
+
+function coroutine.resume(co, ...)
+	return rethrow(resume(co, ...))
+end
+
+local function unwrap(ok, ...)
+	if not ok then
+		error((...), 0)
+	end
+
+	return ...
+end
+
+function coroutine.wrap(fn)
+	local co = create(fn)
+
+	return function(...)
+		return unwrap(rethrow(resume(co, ...)))
+	end
+end
+`
+
+// OpenFlow adds label, jump and call functions to the lua state. It must be opened after the base and coroutine libraries, because it wraps their functions
+func OpenFlow(L *lua.LState) (*Flow, error) {
+	f := Flow{
+		labels: make(map[string]*lua.LFunction),
+	}
+
+	L.SetGlobal("label", L.NewFunction(f.label))
+	L.SetGlobal("jump", L.NewFunction(f.jump))
+
+	fn, err := L.LoadString(flowFunctions)
+	if err != nil {
+		return nil, err
+	}
+
+	L.Push(fn)
+	L.Push(L.NewFunction(f.find))
+	L.Push(L.NewFunction(isJump))
+	if err := L.PCall(2, 0, nil); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// Label returns the function of a label
+func (f *Flow) Label(name string) (*lua.LFunction, bool) {
+	fn, ok := f.labels[name]
+
+	return fn, ok
+}
+
+// Labels returns the sorted names of all labels
+func (f *Flow) Labels() []string {
+	names := make([]string, 0, len(f.labels))
+	for name := range f.labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (f *Flow) label(L *lua.LState) int {
+	name := L.CheckString(1)
+	fn := L.CheckFunction(2)
+
+	if _, exists := f.labels[name]; exists {
+		L.ArgError(1, "label \""+name+"\" is already defined")
+	}
+	f.labels[name] = fn
+
+	return 0
+}
+
+func (f *Flow) jump(L *lua.LState) int {
+	name := L.CheckString(1)
+
+	if _, exists := f.labels[name]; !exists {
+		L.ArgError(1, "label \""+name+"\" is not defined")
+	}
+
+	// Raising an error unwinds the whole coroutine, so a jump never grows the lua stack
+	ud := L.NewUserData()
+	ud.Value = &jump{label: name}
+	L.Error(ud, 0)
+
+	return 0
+}
+
+func (f *Flow) find(L *lua.LState) int {
+	name := L.CheckString(1)
+
+	fn, exists := f.labels[name]
+	if !exists {
+		L.ArgError(1, "label \""+name+"\" is not defined")
+	}
+
+	L.Push(fn)
+
+	return 1
+}
+
+// isJump checks if the value is the error of jump
+func isJump(L *lua.LState) int {
+	ud, ok := L.Get(1).(*lua.LUserData)
+	if ok {
+		_, ok = ud.Value.(*jump)
+	}
+	L.Push(lua.LBool(ok))
+
+	return 1
+}
+
+// jumpTarget checks if err is raised by jump and returns the name of the label
+func jumpTarget(err error) (string, bool) {
+	var apiErr *lua.ApiError
+	if !errors.As(err, &apiErr) {
+		return "", false
+	}
+
+	ud, ok := apiErr.Object.(*lua.LUserData)
+	if !ok {
+		return "", false
+	}
+
+	j, ok := ud.Value.(*jump)
+	if !ok {
+		return "", false
+	}
+
+	return j.label, true
+}
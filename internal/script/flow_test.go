@@ -0,0 +1,182 @@
+package script
+
+import (
+	"slices"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// newTestRunner returns a runner for source. Every call to log in source adds its argument to the returned slice and yield yields the story
+func newTestRunner(t *testing.T, source string) (*Runner, *[]string) {
+	t.Helper()
+
+	L := lua.NewState()
+	t.Cleanup(L.Close)
+
+	logs := []string{}
+	L.SetGlobal("log", L.NewFunction(func(L *lua.LState) int {
+		logs = append(logs, L.CheckString(1))
+		return 0
+	}))
+	L.SetGlobal("yield", L.NewFunction(func(L *lua.LState) int {
+		return L.Yield(lua.LNil)
+	}))
+
+	flow, err := OpenFlow(L)
+	if err != nil {
+		t.Fatalf("OpenFlow returned an error: %v", err)
+	}
+
+	fn, err := L.LoadString(source)
+	if err != nil {
+		t.Fatalf("failed to load the script: %v", err)
+	}
+
+	r, err := NewRunner(L, flow, fn)
+	if err != nil {
+		t.Fatalf("NewRunner returned an error: %v", err)
+	}
+
+	return r, &logs
+}
+
+func TestJumpAndCall(t *testing.T) {
+	r, logs := newTestRunner(t, `
+label("start", function()
+	log("start")
+	call("middle")
+	log("back")
+	jump("end")
+	log("never")
+end)
+
+label("middle", function()
+	yield()
+	log("middle")
+end)
+
+label("end", function()
+	log("end")
+end)
+
+jump("start")
+`)
+
+	for !r.Dead() {
+		if _, err := r.Resume(); err != nil {
+			t.Fatalf("Resume returned an error: %v", err)
+		}
+	}
+
+	expected := []string{"start", "middle", "back", "end"}
+	if !slices.Equal(*logs, expected) {
+		t.Errorf("logs expected %v; got: %v", expected, *logs)
+	}
+
+	if r.Flow.Current != "end" {
+		t.Errorf("current label expected %v; got: %v", "end", r.Flow.Current)
+	}
+}
+
+func TestJumpStack(t *testing.T) {
+	// Every jump starts a new coroutine, so jumping many times must not overflow the lua stack
+	r, _ := newTestRunner(t, `
+n = 0
+
+label("loop", function()
+	n = n + 1
+	if n < 10000 then
+		jump("loop")
+	end
+end)
+
+jump("loop")
+`)
+
+	if _, err := r.Resume(); err != nil {
+		t.Fatalf("Resume returned an error: %v", err)
+	}
+
+	if n := r.L.GetGlobal("n"); n != lua.LNumber(10000) {
+		t.Errorf("n expected %v; got: %v", 10000, n)
+	}
+}
+
+func TestUndefinedLabel(t *testing.T) {
+	r, _ := newTestRunner(t, `jump("nowhere")`)
+
+	if _, err := r.Resume(); err == nil {
+		t.Errorf("jumping to an undefined label should return an error; got: %v", nil)
+	}
+}
+
+func TestJumpInPcall(t *testing.T) {
+	// pcall and xpcall must not catch jumps, but they still catch other errors
+	r, logs := newTestRunner(t, `
+label("xpcall", function()
+	xpcall(function() jump("end") end, function(err) log("handler") end)
+	log("never")
+end)
+
+label("end", function()
+	local ok = pcall(error, "oops")
+	log(tostring(ok))
+end)
+
+pcall(function() jump("xpcall") end)
+log("never")
+`)
+
+	for !r.Dead() {
+		if _, err := r.Resume(); err != nil {
+			t.Fatalf("Resume returned an error: %v", err)
+		}
+	}
+
+	expected := []string{"false"}
+	if !slices.Equal(*logs, expected) {
+		t.Errorf("logs expected %v; got: %v", expected, *logs)
+	}
+
+	if r.Flow.Current != "end" {
+		t.Errorf("current label expected %v; got: %v", "end", r.Flow.Current)
+	}
+}
+
+func TestJumpInCoroutine(t *testing.T) {
+	// Coroutines of the script must not catch jumps, but they still return other errors and values
+	r, logs := newTestRunner(t, `
+label("wrap", function()
+	local f = coroutine.wrap(function() jump("end") end)
+	f()
+	log("never")
+end)
+
+label("end", function()
+	local ok = coroutine.resume(coroutine.create(function() error("oops") end))
+	log(tostring(ok))
+
+	local f = coroutine.wrap(function(a) local b = coroutine.yield(a + 1); return b * 2 end)
+	log(tostring(f(1)) .. " " .. tostring(f(5)))
+end)
+
+coroutine.resume(coroutine.create(function() jump("wrap") end))
+log("never")
+`)
+
+	for !r.Dead() {
+		if _, err := r.Resume(); err != nil {
+			t.Fatalf("Resume returned an error: %v", err)
+		}
+	}
+
+	expected := []string{"false", "2 10"}
+	if !slices.Equal(*logs, expected) {
+		t.Errorf("logs expected %v; got: %v", expected, *logs)
+	}
+
+	if r.Flow.Current != "end" {
+		t.Errorf("current label expected %v; got: %v", "end", r.Flow.Current)
+	}
+}
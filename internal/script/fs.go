@@ -0,0 +1,172 @@
+package script
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// FS gives the game script restricted access to files. Scripts can only read the files inside the game directory and only write to the data directory inside the user data directory of the game
+type FS struct {
+	gameDir string
+	dataDir string
+}
+
+// OpenFS adds the fufu.fs table to the lua state
+func OpenFS(L *lua.LState, gameDir string, saveDir string) *FS {
+	f := FS{
+		gameDir: gameDir,
+		// Scripts have their own directory, so they can't overwrite saves and settings
+		dataDir: filepath.Join(saveDir, "data"),
+	}
+
+	fufu, ok := L.GetGlobal("fufu").(*lua.LTable)
+	if !ok {
+		fufu = L.NewTable()
+		L.SetGlobal("fufu", fufu)
+	}
+
+	L.SetField(fufu, "fs", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"read":       f.read,
+		"exists":     f.exists,
+		"list":       f.list,
+		"read_save":  f.readSave,
+		"write_save": f.writeSave,
+	}))
+
+	return &f
+}
+
+// readFile reads a file inside dir. os.Root doesn't let the path leave dir, even with ".." or symbolic links
+func readFile(dir string, name string) ([]byte, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	file, err := root.Open(filepath.FromSlash(name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// pushError pushes nil and the error message like the functions of the io library
+func pushError(L *lua.LState, err error) int {
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err.Error()))
+
+	return 2
+}
+
+func (f *FS) read(L *lua.LState) int {
+	data, err := readFile(f.gameDir, L.CheckString(1))
+	if err != nil {
+		return pushError(L, err)
+	}
+
+	L.Push(lua.LString(data))
+
+	return 1
+}
+
+func (f *FS) exists(L *lua.LState) int {
+	root, err := os.OpenRoot(f.gameDir)
+	if err != nil {
+		return pushError(L, err)
+	}
+	defer root.Close()
+
+	_, err = root.Stat(filepath.FromSlash(L.CheckString(1)))
+	L.Push(lua.LBool(err == nil))
+
+	return 1
+}
+
+func (f *FS) list(L *lua.LState) int {
+	root, err := os.OpenRoot(f.gameDir)
+	if err != nil {
+		return pushError(L, err)
+	}
+	defer root.Close()
+
+	dir, err := root.Open(filepath.FromSlash(L.OptString(1, ".")))
+	if err != nil {
+		return pushError(L, err)
+	}
+	defer dir.Close()
+
+	entries, err := dir.ReadDir(-1)
+	if err != nil {
+		return pushError(L, err)
+	}
+
+	names := L.NewTable()
+	for _, entry := range entries {
+		names.Append(lua.LString(entry.Name()))
+	}
+	L.Push(names)
+
+	return 1
+}
+
+func (f *FS) readSave(L *lua.LState) int {
+	data, err := readFile(f.dataDir, L.CheckString(1))
+	if err != nil {
+		return pushError(L, err)
+	}
+
+	L.Push(lua.LString(data))
+
+	return 1
+}
+
+func (f *FS) writeSave(L *lua.LState) int {
+	name := filepath.FromSlash(L.CheckString(1))
+	data := L.CheckString(2)
+
+	if err := os.MkdirAll(f.dataDir, 0o755); err != nil {
+		return pushError(L, err)
+	}
+
+	root, err := os.OpenRoot(f.dataDir)
+	if err != nil {
+		return pushError(L, err)
+	}
+	defer root.Close()
+
+	// Create the parent directories of the file one by one inside the root
+	parent := ""
+	for _, dir := range strings.Split(filepath.Dir(name), string(filepath.Separator)) {
+		if dir == "." {
+			continue
+		}
+
+		parent = filepath.Join(parent, dir)
+		if err := root.Mkdir(parent, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+			return pushError(L, err)
+		}
+	}
+
+	file, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return pushError(L, err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(data); err != nil {
+		return pushError(L, err)
+	}
+
+	L.Push(lua.LTrue)
+
+	return 1
+}
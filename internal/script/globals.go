@@ -0,0 +1,119 @@
+package script
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+// CopyGlobals returns a copy of the globals that are not in skip. Tables shared by globals are copied once, so they are still shared in the copy. Functions, threads and userdata can't be copied and are left out
+func CopyGlobals(L *lua.LState, skip map[string]bool) map[string]lua.LValue {
+	globals := make(map[string]lua.LValue)
+	seen := make(map[*lua.LTable]*lua.LTable)
+
+	L.G.Global.ForEach(func(k, v lua.LValue) {
+		name, ok := k.(lua.LString)
+		if !ok || skip[string(name)] {
+			return
+		}
+
+		if cv, ok := copyValue(L, v, seen); ok {
+			globals[string(name)] = cv
+		}
+	})
+
+	return globals
+}
+
+// RestoreGlobals sets the globals back to their copies. Tables of the script are changed in place, so locals and closures that reference them see the restored values, and fields that can't be copied like methods are kept. The copies aren't changed, so they can be restored again
+func RestoreGlobals(L *lua.LState, globals map[string]lua.LValue) {
+	restored := make(map[*lua.LTable]*lua.LTable)
+
+	for name, v := range globals {
+		L.SetGlobal(name, restoreValue(L, L.GetGlobal(name), v, restored))
+	}
+}
+
+// copyValue returns a deep copy of a lua value. The second return value is false for values that can't be copied
+func copyValue(L *lua.LState, v lua.LValue, seen map[*lua.LTable]*lua.LTable) (lua.LValue, bool) {
+	switch value := v.(type) {
+	case lua.LString, lua.LNumber, lua.LBool, *lua.LNilType:
+		return value, true
+	case *lua.LTable:
+		// Tables that reference each other are copied once
+		if t, ok := seen[value]; ok {
+			return t, true
+		}
+
+		t := L.NewTable()
+		seen[value] = t
+		value.ForEach(func(k, v lua.LValue) {
+			ck, ok := copyValue(L, k, seen)
+			if !ok {
+				return
+			}
+
+			if cv, ok := copyValue(L, v, seen); ok {
+				t.RawSet(ck, cv)
+			}
+		})
+
+		if mt, ok := value.Metatable.(*lua.LTable); ok {
+			if cmt, ok := copyValue(L, mt, seen); ok {
+				t.Metatable = cmt
+			}
+		}
+
+		return t, true
+	}
+
+	return lua.LNil, false
+}
+
+// copyable checks if copyValue can copy the value
+func copyable(v lua.LValue) bool {
+	switch v.(type) {
+	case lua.LString, lua.LNumber, lua.LBool, *lua.LTable:
+		return true
+	}
+
+	return false
+}
+
+// restoreValue returns the value of the copy for the live value. A copied table is written into the live table if there is one. restored keeps the live table of every copied table, so shared tables stay shared
+func restoreValue(L *lua.LState, live lua.LValue, v lua.LValue, restored map[*lua.LTable]*lua.LTable) lua.LValue {
+	value, ok := v.(*lua.LTable)
+	if !ok {
+		return v
+	}
+
+	if t, ok := restored[value]; ok {
+		return t
+	}
+
+	t, ok := live.(*lua.LTable)
+	if !ok {
+		t = L.NewTable()
+	}
+	restored[value] = t
+
+	// Fields that could be copied but are not in the copy are added after it. Other fields are kept
+	var removed []lua.LValue
+	t.ForEach(func(k, lv lua.LValue) {
+		if copyable(lv) && value.RawGet(k) == lua.LNil {
+			removed = append(removed, k)
+		}
+	})
+	for _, k := range removed {
+		t.RawSet(k, lua.LNil)
+	}
+
+	value.ForEach(func(k, cv lua.LValue) {
+		k = restoreValue(L, lua.LNil, k, restored)
+		t.RawSet(k, restoreValue(L, t.RawGet(k), cv, restored))
+	})
+
+	if mt, ok := value.Metatable.(*lua.LTable); ok {
+		t.Metatable = restoreValue(L, t.Metatable, mt, restored)
+	}
+
+	return t
+}
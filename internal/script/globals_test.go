@@ -0,0 +1,64 @@
+package script
+
+import (
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestRestoreGlobals(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	if err := L.DoString(`
+player = {hp = 10, items = {"key"}}
+function player.heal(self) self.hp = self.hp + 1 end
+other = player
+
+local p = player
+function get_hp() return p.hp end
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	globals := CopyGlobals(L, map[string]bool{"get_hp": true})
+
+	// The script changes the table after the copy and a rollback sets it back
+	if err := L.DoString(`player.hp = 3; player.gold = 5; player.items[2] = "map"`); err != nil {
+		t.Fatal(err)
+	}
+
+	RestoreGlobals(L, globals)
+
+	// Checks run in order, because the method changes the table
+	checks := []struct {
+		name string
+		code string
+	}{
+		{"values", `return player.hp == 10 and player.gold == nil and #player.items == 1`},
+		{"function", `return type(player.heal) == "function"`},
+		{"local", `return get_hp() == 10`},
+		{"shared", `return rawequal(player, other)`},
+		{"method", `player:heal(); return get_hp() == 11`},
+	}
+
+	for _, check := range checks {
+		if err := L.DoString(check.code); err != nil {
+			t.Fatalf("%s: %v", check.name, err)
+		}
+
+		if v := L.Get(-1); v != lua.LTrue {
+			t.Errorf("%s: expected %v; got: %v", check.name, lua.LTrue, v)
+		}
+		L.Pop(1)
+	}
+
+	// Restoring the same copy again must give the values of the copy
+	RestoreGlobals(L, globals)
+	if err := L.DoString(`return get_hp()`); err != nil {
+		t.Fatal(err)
+	}
+	if v := L.Get(-1); v != lua.LNumber(10) {
+		t.Errorf("second restore: expected %v; got: %v", 10, v)
+	}
+}
@@ -0,0 +1,100 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/pm"
+)
+
+// Input is the properties of a call of the input function
+type Input struct {
+	Default string
+	// MaxLength is the maximum number of characters. It's 0 for inputs without a limit
+	MaxLength int
+	// Allowed is a lua pattern like "[%w ]" that every typed character must match. Empty pattern allows every character
+	Allowed string
+}
+
+// ReadInput reads the properties of an input. Properties that are not valid are ignored and returned as problems
+func ReadInput(properties *lua.LTable) (*Input, []string) {
+	in := Input{}
+	var problems []string
+
+	if properties == nil {
+		return &in, nil
+	}
+
+	switch allowed := properties.RawGetString("allowed").(type) {
+	case lua.LString:
+		if _, err := pm.Find(string(allowed), []byte("a"), 0, 1); err != nil {
+			problems = append(problems, fmt.Sprintf("allowed is not a valid pattern: %v", err))
+			break
+		}
+		in.Allowed = string(allowed)
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("allowed must be a string; got %s", allowed.Type()))
+	}
+
+	switch maxLength := properties.RawGetString("max_length").(type) {
+	case lua.LNumber:
+		if maxLength < 1 {
+			problems = append(problems, fmt.Sprintf("max_length must be a positive number; got %v", maxLength))
+			break
+		}
+		in.MaxLength = int(maxLength)
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("max_length must be a number; got %s", maxLength.Type()))
+	}
+
+	switch def := properties.RawGetString("default").(type) {
+	case lua.LString:
+		in.Default = in.Clean(string(def))
+		if in.Default != string(def) {
+			problems = append(problems, fmt.Sprintf("default %q is longer than max_length or has characters that are not allowed", string(def)))
+		}
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("default must be a string; got %s", def.Type()))
+	}
+
+	return &in, problems
+}
+
+// Allows checks if the pattern of the input matches the whole character
+func (in *Input) Allows(char string) bool {
+	if in.Allowed == "" {
+		return true
+	}
+
+	matches, err := pm.Find(in.Allowed, []byte(char), 0, 1)
+	if err != nil || len(matches) == 0 {
+		return false
+	}
+
+	return matches[0].Capture(0) == 0 && matches[0].Capture(1) == len(char)
+}
+
+// Clean removes the characters that are not allowed from value and cuts it to the maximum length
+func (in *Input) Clean(value string) string {
+	var b strings.Builder
+	n := 0
+
+	for _, r := range value {
+		if in.MaxLength > 0 && n >= in.MaxLength {
+			break
+		}
+
+		if !in.Allows(string(r)) {
+			continue
+		}
+
+		b.WriteRune(r)
+		n++
+	}
+
+	return b.String()
+}
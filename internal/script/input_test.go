@@ -0,0 +1,53 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReadInput(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	if err := L.DoString(`properties = {default = "Fufu", max_length = 6, allowed = "[%a ]"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	in, problems := ReadInput(L.GetGlobal("properties").(*lua.LTable))
+	if len(problems) > 0 {
+		t.Fatalf("expected no problems; got: %q", problems)
+	}
+
+	if *in != (Input{Default: "Fufu", MaxLength: 6, Allowed: "[%a ]"}) {
+		t.Errorf("input: expected the properties of the table; got: %+v", *in)
+	}
+
+	if in.Allows("1") || !in.Allows("b") || !in.Allows(" ") {
+		t.Errorf("allowed characters: expected letters and spaces")
+	}
+
+	if got := in.Clean("R2-D2 unit"); got != "RD uni" {
+		t.Errorf("clean: expected \"RD uni\"; got: %q", got)
+	}
+}
+
+func TestReadInputProblems(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	if err := L.DoString(`properties = {default = 1, max_length = 0, allowed = "[%a"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	_, problems := ReadInput(L.GetGlobal("properties").(*lua.LTable))
+	expected := []string{
+		"allowed is not a valid pattern: unexpected EOS at 2",
+		"max_length must be a positive number; got 0",
+		"default must be a string; got number",
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("problems: expected %q; got: %q", expected, problems)
+	}
+}
@@ -0,0 +1,167 @@
+package script
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// Loader loads script files from the game root directory. Compiled files are cached, so loading a file again (for example after replaying the story) doesn't need to parse it again
+type Loader struct {
+	root   string
+	protos map[string]*lua.FunctionProto
+}
+
+// loaderFunctions defines require and include in lua. Files must be called from lua and not go, because the story can yield inside them
+const loaderFunctions = `
+local load = ...
+local loaded = {}
+
+function include(path, ...)
+	return load(path, false)(...)
+end
+
+function require(name)
+	if loaded[name] == nil then
+		local result = load(name, true)(name)
+		if result == nil then
+			result = true
+		end
+		loaded[name] = result
+	end
+
+	return loaded[name]
+end
+`
+
+// NewLoader returns a Loader that loads files inside root
+func NewLoader(root string) *Loader {
+	return &Loader{
+		root:   root,
+		protos: make(map[string]*lua.FunctionProto),
+	}
+}
+
+// Open adds require and include functions to the lua state. require runs a module once and returns its result every time and include runs a file every time it's called
+func (l *Loader) Open(L *lua.LState) error {
+	fn, err := L.LoadString(loaderFunctions)
+	if err != nil {
+		return err
+	}
+
+	L.Push(fn)
+	L.Push(L.NewFunction(l.load))
+
+	return L.PCall(1, 0, nil)
+}
+
+// Load returns the file in path as a lua function. path is relative to the game root and uses "/" as separator
+func (l *Loader) Load(L *lua.LState, path string) (*lua.LFunction, error) {
+	proto, err := l.compile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return L.NewFunctionFromProto(proto), nil
+}
+
+// Clear removes all cached files, so they will be read again from the disk
+func (l *Loader) Clear() {
+	l.protos = make(map[string]*lua.FunctionProto)
+}
+
+// Files returns the files that are loaded since the last Clear
+func (l *Loader) Files() []string {
+	files := make([]string, 0, len(l.protos))
+	for file := range l.protos {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	return files
+}
+
+// Root returns the game root directory
+func (l *Loader) Root() string {
+	return l.root
+}
+
+// compile reads and compiles a file or returns the cached one
+func (l *Loader) compile(file string) (*lua.FunctionProto, error) {
+	file = path.Clean(filepath.ToSlash(file))
+
+	if proto, exists := l.protos[file]; exists {
+		return proto, nil
+	}
+
+	chunk, err := l.parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	proto, err := lua.Compile(chunk, file)
+	if err != nil {
+		return nil, &Error{File: file, Message: err.Error()}
+	}
+
+	l.protos[file] = proto
+
+	return proto, nil
+}
+
+// parse reads a file inside the game root and returns its syntax tree
+func (l *Loader) parse(file string) ([]ast.Stmt, error) {
+	if !filepath.IsLocal(file) {
+		return nil, &Error{File: file, Message: "file is outside of the game directory"}
+	}
+
+	data, err := os.ReadFile(filepath.Join(l.root, filepath.FromSlash(file)))
+	if err != nil {
+		return nil, &Error{File: file, Message: err.Error()}
+	}
+
+	// File name is used as the chunk name, so runtime errors show the file and the line
+	chunk, err := parse.Parse(bytes.NewReader(data), file)
+	if err != nil {
+		return nil, syntaxError(file, err)
+	}
+
+	return chunk, nil
+}
+
+func (l *Loader) load(L *lua.LState) int {
+	name := L.CheckString(1)
+	module := L.OptBool(2, false)
+
+	file := name
+	if module {
+		file = moduleFile(name)
+	}
+
+	fn, err := l.Load(L, file)
+	if err != nil {
+		// Level 3 skips this function and the lua side of require and include, so the error shows where they are called in the game script
+		L.Error(lua.LString(fmt.Sprintf("cannot load %s: %s", name, err)), 3)
+	}
+
+	L.Push(fn)
+
+	return 1
+}
+
+// moduleFile changes a module name like "chapters.one" to its file "chapters/one.lua"
+func moduleFile(name string) string {
+	if strings.HasSuffix(name, ".lua") {
+		return name
+	}
+
+	return fmt.Sprintf("%s.lua", strings.ReplaceAll(name, ".", "/"))
+}
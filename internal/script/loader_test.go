@@ -0,0 +1,167 @@
+package script
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// writeFiles creates files inside a temporary game root directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func TestRequireAndInclude(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"main.lua": `
+local one = require("chapters.one")
+local again = require("chapters.one")
+include("chapters/two.lua")
+include("chapters/two.lua")
+result = one.name .. " " .. tostring(one == again) .. " " .. runs .. " " .. includes
+`,
+		"chapters/one.lua": `
+runs = (runs or 0) + 1
+return { name = "one" }
+`,
+		"chapters/two.lua": `includes = (includes or 0) + 1`,
+	})
+
+	L := lua.NewState()
+	defer L.Close()
+
+	loader := NewLoader(root)
+	if err := loader.Open(L); err != nil {
+		t.Fatalf("Open returned an error: %v", err)
+	}
+
+	fn, err := loader.Load(L, "main.lua")
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	L.Push(fn)
+	if err := L.PCall(0, 0, nil); err != nil {
+		t.Fatalf("main.lua returned an error: %v", err)
+	}
+
+	if result := L.GetGlobal("result").String(); result != "one true 1 2" {
+		t.Errorf("result expected %q; got: %q", "one true 1 2", result)
+	}
+
+	if len(loader.protos) != 3 {
+		t.Errorf("every file should be compiled once; cached files expected: %v; got: %v", 3, len(loader.protos))
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"main.lua":   "include('broken.lua')",
+		"broken.lua": "local x = \n\nif then",
+		"escape.lua": "include('../outside.lua')",
+	})
+
+	L := lua.NewState()
+	defer L.Close()
+
+	loader := NewLoader(root)
+	if err := loader.Open(L); err != nil {
+		t.Fatalf("Open returned an error: %v", err)
+	}
+
+	tests := map[string]string{
+		"main.lua":   "broken.lua:3:",
+		"escape.lua": "outside of the game directory",
+	}
+
+	for file, expected := range tests {
+		fn, err := loader.Load(L, file)
+		if err != nil {
+			t.Fatalf("Load returned an error: %v", err)
+		}
+
+		L.Push(fn)
+		err = L.PCall(0, 0, nil)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("error of %s should contain %q; got: %v", file, expected, err)
+		}
+
+		if err != nil && !strings.HasPrefix(err.Error(), file+":1:") {
+			t.Errorf("error of %s should start with the place of the include; got: %v", file, err)
+		}
+	}
+}
+
+func TestFindCalls(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"main.lua": `
+narrate("one")
+if false then
+	say(c,
+		"two")
+end
+label("x", function()
+	local r = choice({"a", narrate("three")})
+end)
+obj:narrate("method")
+local narrate_later = narrate
+`,
+	})
+
+	calls, err := NewLoader(root).FindCalls("./main.lua", "narrate", "say")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found []string
+	for _, call := range calls {
+		found = append(found, fmt.Sprintf("%s:%d:%s:%d", call.File, call.Line, call.Name, len(call.Args)))
+	}
+
+	expected := []string{"main.lua:2:narrate:1", "main.lua:4:say:2", "main.lua:8:narrate:1"}
+	if !slices.Equal(found, expected) {
+		t.Errorf("calls: expected %v; got: %v", expected, found)
+	}
+}
+
+func TestFindFiles(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"main.lua": `
+require("chapters.one")
+include("chapters/two.lua")
+include(dynamic_path)
+`,
+		"chapters/one.lua":   `require("chapters.one") include("./chapters/three.lua")`,
+		"chapters/two.lua":   ``,
+		"chapters/three.lua": ``,
+	})
+
+	files, err := NewLoader(root).FindFiles("main.lua")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"main.lua", "chapters/one.lua", "chapters/two.lua", "chapters/three.lua"}
+	if !slices.Equal(files, expected) {
+		t.Errorf("files: expected %v; got: %v", expected, files)
+	}
+}
@@ -0,0 +1,190 @@
+package script
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/moheb2000/fufu/internal/color"
+	"github.com/moheb2000/fufu/internal/particles"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ReadParticles reads the table of particles like particles{name = "dust", rate = 20, lifetime = {2000, 4000}, size = {4, 1}}. Fields that are not set keep the values of particles.DefaultConfig. Ranges are a number or {min, max} and values over the life of particles are a number or {start, end}
+func ReadParticles(t *lua.LTable) (*particles.Config, []string) {
+	c := particles.DefaultConfig()
+	var problems []string
+
+	if t == nil {
+		return &c, []string{"particles must be a table"}
+	}
+
+	str := func(field string, v *string, values []string) {
+		switch s := t.RawGetString(field).(type) {
+		case lua.LString:
+			if values != nil && !slices.Contains(values, string(s)) {
+				problems = append(problems, fmt.Sprintf("%s of particles must be one of %s; got %q", field, strings.Join(values, ", "), string(s)))
+				return
+			}
+			*v = string(s)
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("%s of particles must be a string; got %s", field, s.Type()))
+		}
+	}
+
+	num := func(field string, v *float64) {
+		switch n := t.RawGetString(field).(type) {
+		case lua.LNumber:
+			*v = float64(n)
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("%s of particles must be a number; got %s", field, n.Type()))
+		}
+	}
+
+	// pair reads a number or a table of two numbers. scale changes the unit of the numbers, like milliseconds of lifetime to seconds
+	pair := func(field string, first *float64, second *float64, scale float64) {
+		switch v := t.RawGetString(field).(type) {
+		case lua.LNumber:
+			*first = float64(v) * scale
+			*second = float64(v) * scale
+		case *lua.LTable:
+			a, aok := v.RawGetInt(1).(lua.LNumber)
+			b, bok := v.RawGetInt(2).(lua.LNumber)
+			if !aok || !bok {
+				problems = append(problems, fmt.Sprintf("%s of particles must be a number or a table of two numbers", field))
+				return
+			}
+			*first = float64(a) * scale
+			*second = float64(b) * scale
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("%s of particles must be a number or a table of two numbers; got %s", field, v.Type()))
+		}
+	}
+
+	str("name", &c.Name, nil)
+	str("shape", &c.Shape, particles.SHAPES)
+	str("texture", &c.Texture, nil)
+	str("color", &c.Color, nil)
+	str("layer", &c.Layer, particles.LAYERS)
+
+	switch area := t.RawGetString("area").(type) {
+	case *lua.LTable:
+		for field, v := range map[string]*float64{"x": &c.X, "y": &c.Y, "w": &c.W, "h": &c.H} {
+			switch n := area.RawGetString(field).(type) {
+			case lua.LNumber:
+				*v = float64(n)
+			case *lua.LNilType:
+			default:
+				problems = append(problems, fmt.Sprintf("%s of particles area must be a number; got %s", field, n.Type()))
+			}
+		}
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("area of particles must be a table like {x = 0, y = 0, w = 1, h = 1}; got %s", area.Type()))
+	}
+
+	num("rate", &c.Rate)
+	pair("lifetime", &c.Lifetime.Min, &c.Lifetime.Max, 0.001)
+	pair("speed", &c.Speed.Min, &c.Speed.Max, 1)
+	pair("direction", &c.Direction.Min, &c.Direction.Max, 1)
+	pair("rotation", &c.Rotation.Min, &c.Rotation.Max, 1)
+	pair("spin", &c.Spin.Min, &c.Spin.Max, 1)
+	pair("size", &c.Size.Start, &c.Size.End, 1)
+	pair("alpha", &c.Alpha.Start, &c.Alpha.End, 1)
+	num("stretch", &c.Stretch)
+
+	switch gravity := t.RawGetString("gravity").(type) {
+	case lua.LNumber:
+		c.GravityY = float64(gravity)
+	case *lua.LNilType:
+	default:
+		pair("gravity", &c.GravityX, &c.GravityY, 1)
+	}
+
+	// max is checked before it's converted, because too large numbers don't fit in an int
+	limit := float64(c.Max)
+	num("max", &limit)
+	if limit < 0 || limit > particles.MAX_PARTICLES {
+		problems = append(problems, fmt.Sprintf("max of particles must be from 0 to %v; got %v", particles.MAX_PARTICLES, limit))
+	} else {
+		c.Max = int(limit)
+	}
+
+	for field, v := range map[string]*bool{"align": &c.Align, "warm": &c.Warm} {
+		switch b := t.RawGetString(field).(type) {
+		case lua.LBool:
+			*v = bool(b)
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("%s of particles must be a boolean; got %s", field, b.Type()))
+		}
+	}
+
+	if _, err := color.ParseHex(c.Color); err != nil {
+		problems = append(problems, fmt.Sprintf("color of particles: %v", err))
+	}
+
+	if c.Rate < 0 {
+		problems = append(problems, fmt.Sprintf("rate of particles must not be negative; got %v", c.Rate))
+	}
+
+	if c.Lifetime.Min <= 0 || c.Lifetime.Max < c.Lifetime.Min {
+		problems = append(problems, "lifetime of particles must be more than 0 and its max must not be less than its min")
+	}
+
+	// Problems of maps are found in random order, so they are sorted to be the same in every run
+	slices.Sort(problems)
+
+	return &c, problems
+}
+
+// ReadWeather reads the arguments of weather. It returns nil for "clear" or nil, which stop the weather
+func ReadWeather(name lua.LValue, properties *lua.LTable) (*particles.Config, []string) {
+	n, ok := name.(lua.LString)
+	if name == lua.LNil || n == "clear" {
+		return nil, nil
+	}
+	if !ok {
+		return nil, []string{fmt.Sprintf("weather must be a string; got %s", name.Type())}
+	}
+
+	intensity := 0.5
+	layer := "front"
+	var problems []string
+	if properties != nil {
+		switch i := properties.RawGetString("intensity").(type) {
+		case lua.LNumber:
+			if i < 0 || i > 1 {
+				problems = append(problems, fmt.Sprintf("intensity of weather must be a number from 0 to 1; got %v", i))
+				break
+			}
+			intensity = float64(i)
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("intensity of weather must be a number; got %s", i.Type()))
+		}
+
+		switch l := properties.RawGetString("layer").(type) {
+		case lua.LString:
+			if !slices.Contains(particles.LAYERS, string(l)) {
+				problems = append(problems, fmt.Sprintf("layer of weather must be one of %s; got %q", strings.Join(particles.LAYERS, ", "), string(l)))
+				break
+			}
+			layer = string(l)
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("layer of weather must be a string; got %s", l.Type()))
+		}
+	}
+
+	c, ok := particles.Weather(string(n), intensity)
+	if !ok {
+		return nil, append(problems, fmt.Sprintf("weather must be one of clear, %s; got %q", particles.WeatherNames(), string(n)))
+	}
+	c.Layer = layer
+
+	return &c, problems
+}
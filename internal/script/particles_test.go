@@ -0,0 +1,78 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moheb2000/fufu/internal/particles"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReadParticles(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	expected := particles.DefaultConfig()
+	expected.Name = "embers"
+	expected.Shape = "line"
+	expected.Y, expected.H = 1, 0
+	expected.Rate = 30
+	expected.Lifetime = particles.Range{Min: 1, Max: 2.5}
+	expected.Direction = particles.Range{Min: 260, Max: 280}
+	expected.GravityY = -20
+	expected.Size = particles.Over{Start: 5, End: 1}
+	expected.Alpha = particles.Over{Start: 1, End: 0}
+	expected.Color = "#ff8800"
+	expected.Layer = "back"
+	expected.Warm = true
+
+	if err := L.DoString(`value = {name = "embers", shape = "line", area = {y = 1, h = 0}, rate = 30, lifetime = {1000, 2500}, direction = {260, 280}, gravity = -20, size = {5, 1}, alpha = {1, 0}, color = "#ff8800", layer = "back", warm = true}`); err != nil {
+		t.Fatal(err)
+	}
+
+	c, problems := ReadParticles(L.GetGlobal("value").(*lua.LTable))
+	if !reflect.DeepEqual(c, &expected) || problems != nil {
+		t.Errorf("embers: expected %+v; got: %+v and %q", expected, c, problems)
+	}
+
+	if err := L.DoString(`value = {shape = "circle", rate = -1, size = {1}, align = "yes", lifetime = 0, max = 1e20}`); err != nil {
+		t.Fatal(err)
+	}
+
+	_, problems = ReadParticles(L.GetGlobal("value").(*lua.LTable))
+	expectedProblems := []string{
+		"align of particles must be a boolean; got string",
+		"lifetime of particles must be more than 0 and its max must not be less than its min",
+		"max of particles must be from 0 to 10000; got 1e+20",
+		"rate of particles must not be negative; got -1",
+		`shape of particles must be one of point, line, rect; got "circle"`,
+		"size of particles must be a number or a table of two numbers",
+	}
+	if !reflect.DeepEqual(problems, expectedProblems) {
+		t.Errorf("problems: expected %q; got: %q", expectedProblems, problems)
+	}
+}
+
+func TestReadWeather(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	properties := L.NewTable()
+	properties.RawSetString("intensity", lua.LNumber(1))
+	properties.RawSetString("layer", lua.LString("back"))
+
+	c, problems := ReadWeather(lua.LString("rain"), properties)
+	if c == nil || c.Name != particles.WEATHER_NAME || c.Layer != "back" || problems != nil {
+		t.Errorf("rain: expected the weather emitter in the back layer; got: %+v and %q", c, problems)
+	}
+
+	if c, problems := ReadWeather(lua.LString("clear"), nil); c != nil || problems != nil {
+		t.Errorf("clear: expected no emitter; got: %+v and %q", c, problems)
+	}
+
+	_, problems = ReadWeather(lua.LString("fog"), nil)
+	expected := []string{`weather must be one of clear, dust, petals, rain, snow; got "fog"`}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("fog: expected problems %q; got: %q", expected, problems)
+	}
+}
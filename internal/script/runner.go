@@ -0,0 +1,148 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Runner runs the story in a coroutine. When the story jumps to a label, Runner throws away the old coroutine and runs the label in a new one
+type Runner struct {
+	L         *lua.LState
+	Flow      *Flow
+	co        *lua.LState
+	bootstrap *lua.LFunction
+	traceback string
+}
+
+// bootstrap starts the story coroutine and pauses it before running the story. gopher-lua replaces the panic handler of a coroutine when it starts, so Runner sets its own handler during the pause
+const bootstrap = `
+local pause, fn = ...
+pause()
+return fn()
+`
+
+// errorPosition matches the position at the start of lua runtime errors like "main.lua:12: attempt to call a nil value"
+var errorPosition = regexp.MustCompile(`(?s)^([^\n]+?):(\d+): (.*)$`)
+
+// NewRunner returns a Runner that runs fn as the story
+func NewRunner(L *lua.LState, flow *Flow, fn *lua.LFunction) (*Runner, error) {
+	r := Runner{
+		L:    L,
+		Flow: flow,
+	}
+
+	bootstrap, err := L.LoadString(bootstrap)
+	if err != nil {
+		return nil, err
+	}
+	r.bootstrap = bootstrap
+
+	if err := r.start(fn); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// start runs fn in a new coroutine until the pause of bootstrap
+func (r *Runner) start(fn *lua.LFunction) error {
+	r.co, _ = r.L.NewThread()
+
+	pause := r.L.NewFunction(func(L *lua.LState) int {
+		return L.Yield()
+	})
+
+	_, err, _ := r.L.Resume(r.co, r.bootstrap, pause, fn)
+	if err != nil {
+		return err
+	}
+
+	r.co.Panic = r.panic
+
+	return nil
+}
+
+// panic keeps the traceback of errors before the coroutine stack is unwound and then raises the error like the default handler of gopher-lua
+func (r *Runner) panic(L *lua.LState) {
+	lines := []string{"stack traceback:"}
+	for level := 0; ; level++ {
+		dbg, ok := L.GetStack(level)
+		if !ok {
+			break
+		}
+
+		if _, err := L.GetInfo("nSl", dbg, lua.LNil); err != nil {
+			continue
+		}
+
+		// Functions written in go don't have a position
+		where := "[G]"
+		if dbg.What != "G" {
+			where = fmt.Sprintf("%s:%d", dbg.Source, dbg.CurrentLine)
+		}
+
+		switch {
+		case dbg.What == "main":
+			lines = append(lines, fmt.Sprintf("\t%s: in main chunk", where))
+		case dbg.Name != "":
+			lines = append(lines, fmt.Sprintf("\t%s: in function '%s'", where, dbg.Name))
+		default:
+			lines = append(lines, fmt.Sprintf("\t%s: in function <%s:%d>", where, dbg.Source, dbg.LineDefined))
+		}
+	}
+	r.traceback = strings.Join(lines, "\n")
+
+	panic(&lua.ApiError{Type: lua.ApiErrorRun, Object: L.Get(-1)})
+}
+
+// Resume continues the story until the next yield. The arguments are returned by the function that yielded the story. Errors of the script are returned as *Error with the traceback of the error
+func (r *Runner) Resume(args ...lua.LValue) (lua.ResumeState, error) {
+	state, err, _ := r.L.Resume(r.co, nil, args...)
+
+	for err != nil {
+		name, ok := jumpTarget(err)
+		if !ok {
+			return state, r.scriptError(err)
+		}
+
+		r.Flow.Current = name
+		if err := r.start(r.Flow.labels[name]); err != nil {
+			return lua.ResumeError, err
+		}
+
+		state, err, _ = r.L.Resume(r.co, nil)
+	}
+
+	return state, err
+}
+
+// Dead checks if the story is finished or stopped with an error
+func (r *Runner) Dead() bool {
+	return r.L.Status(r.co) == "dead"
+}
+
+// scriptError changes a lua error to Error
+func (r *Runner) scriptError(err error) error {
+	var apiErr *lua.ApiError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	e := Error{
+		Message:   apiErr.Object.String(),
+		Traceback: r.traceback,
+	}
+
+	if m := errorPosition.FindStringSubmatch(e.Message); m != nil {
+		e.File = m[1]
+		e.Line, _ = strconv.Atoi(m[2])
+		e.Message = m[3]
+	}
+
+	return &e
+}
@@ -0,0 +1,68 @@
+package script
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestRunnerError(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	L.SetGlobal("yield", L.NewFunction(func(L *lua.LState) int {
+		return L.Yield(lua.LNil)
+	}))
+
+	flow, err := OpenFlow(L)
+	if err != nil {
+		t.Fatalf("OpenFlow returned an error: %v", err)
+	}
+
+	fn, err := L.Load(strings.NewReader(`local function fail()
+	error("boom")
+end
+
+label("chapter", function()
+	yield()
+	fail()
+end)
+
+jump("chapter")
+`), "story.lua")
+	if err != nil {
+		t.Fatalf("failed to load the script: %v", err)
+	}
+
+	r, err := NewRunner(L, flow, fn)
+	if err != nil {
+		t.Fatalf("NewRunner returned an error: %v", err)
+	}
+
+	if _, err := r.Resume(); err != nil {
+		t.Fatalf("first Resume returned an error: %v", err)
+	}
+
+	_, err = r.Resume()
+
+	var scriptErr *Error
+	if !errors.As(err, &scriptErr) {
+		t.Fatalf("Resume should return *Error for script errors; got: %v", err)
+	}
+
+	if scriptErr.File != "story.lua" || scriptErr.Line != 2 || scriptErr.Message != "boom" {
+		t.Errorf("error expected story.lua:2: boom; got: %v", scriptErr)
+	}
+
+	for _, expected := range []string{"story.lua:2: in function 'fail'", "story.lua:7:"} {
+		if !strings.Contains(scriptErr.Traceback, expected) {
+			t.Errorf("traceback should contain %q; got:\n%s", expected, scriptErr.Traceback)
+		}
+	}
+
+	if !r.Dead() {
+		t.Errorf("story should be dead after an error")
+	}
+}
@@ -0,0 +1,59 @@
+package script
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+// safeLibs are the standard libraries that can't access the files, the operating system or the internals of the lua VM
+var safeLibs = map[string]lua.LGFunction{
+	lua.BaseLibName:      lua.OpenBase,
+	lua.TabLibName:       lua.OpenTable,
+	lua.StringLibName:    lua.OpenString,
+	lua.MathLibName:      lua.OpenMath,
+	lua.CoroutineLibName: lua.OpenCoroutine,
+}
+
+// unsafeBaseFunctions are the functions of the base library that read files, show the internals of the VM or reach the loaded libraries and environments of other functions. require is added again by Loader without access to the libraries of the VM
+var unsafeBaseFunctions = []string{"dofile", "loadfile", "_printregs", "module", "require", "getfenv", "setfenv", "newproxy"}
+
+// safeOsFunctions are the functions of the os library that only work with time
+var safeOsFunctions = []string{"clock", "date", "difftime", "time"}
+
+// NewState returns a new lua state. If sandbox is true, only the safe standard libraries are opened. Games can use fufu.fs instead of io and os for working with files
+func NewState(sandbox bool) *lua.LState {
+	if !sandbox {
+		return lua.NewState()
+	}
+
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+
+	for name, open := range safeLibs {
+		L.Push(L.NewFunction(open))
+		L.Push(lua.LString(name))
+		L.Call(1, 0)
+	}
+
+	for _, name := range unsafeBaseFunctions {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	// Only keep time functions of os library, so games can still use time for things like random seeds. The library is opened in another state, because opening it in L registers the whole library in the loaded libraries of L
+	osState := lua.NewState(lua.Options{SkipOpenLibs: true})
+	defer osState.Close()
+
+	osState.Push(osState.NewFunction(lua.OpenOs))
+	osState.Push(lua.LString(lua.OsLibName))
+	osState.Call(1, 1)
+	osLib := osState.CheckTable(-1)
+
+	safeOs := L.NewTable()
+	for _, name := range safeOsFunctions {
+		if fn, ok := osLib.RawGetString(name).(*lua.LFunction); ok && fn.IsG {
+			safeOs.RawSetString(name, L.NewFunction(fn.GFunction))
+		}
+	}
+	L.SetGlobal(lua.OsLibName, safeOs)
+	L.GetField(L.Get(lua.RegistryIndex), "_LOADED").(*lua.LTable).RawSetString(lua.OsLibName, safeOs)
+
+	return L
+}
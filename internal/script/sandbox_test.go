@@ -0,0 +1,146 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// newSandbox returns a sandboxed lua state with fufu.fs for a game directory with one file and a secret file outside of it
+func newSandbox(t *testing.T) (*lua.LState, string, string) {
+	t.Helper()
+
+	base := t.TempDir()
+	gameDir := filepath.Join(base, "game")
+	saveDir := filepath.Join(base, "save")
+	for _, dir := range []string{gameDir, saveDir} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(gameDir, "story.txt"), []byte("once upon a time"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(base, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A symbolic link inside the game directory must not give access to the files outside of it
+	if err := os.Symlink(filepath.Join(base, "secret.txt"), filepath.Join(gameDir, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	L := NewState(true)
+	t.Cleanup(L.Close)
+	OpenFS(L, gameDir, saveDir)
+
+	return L, base, saveDir
+}
+
+func TestSandboxLibraries(t *testing.T) {
+	L, _, _ := newSandbox(t)
+
+	// Every expression must be nil in the sandbox
+	blocked := []string{
+		"io",
+		"debug",
+		"package",
+		"channel",
+		"dofile",
+		"loadfile",
+		"module",
+		"require",
+		"getfenv",
+		"setfenv",
+		"newproxy",
+		"os.execute",
+		"os.exit",
+		"os.getenv",
+		"os.remove",
+		"os.rename",
+		"os.tmpname",
+	}
+
+	for _, expr := range blocked {
+		if err := L.DoString("return " + expr); err != nil {
+			t.Fatalf("failed to run %q: %v", expr, err)
+		}
+
+		if v := L.Get(-1); v != lua.LNil {
+			t.Errorf("%s should not be available in the sandbox; got: %v", expr, v)
+		}
+		L.Pop(1)
+	}
+
+	if err := L.DoString(`assert(type(os.time()) == "number" and string.upper("a") == "A" and math.floor(1.5) == 1)`); err != nil {
+		t.Errorf("safe libraries should be available in the sandbox; got: %v", err)
+	}
+}
+
+func TestSandboxLoadedLibraries(t *testing.T) {
+	L, _, _ := newSandbox(t)
+
+	// The functions of the base library must not reach the whole os library from the loaded libraries
+	escapes := map[string]string{
+		"require": `return require("os").execute`,
+		"module":  `return (function() module("os"); return execute end)()`,
+		"setfenv": `return (function() setfenv(1, {}); return getfenv(0).os.execute end)()`,
+	}
+
+	for name, code := range escapes {
+		// Errors are fine too, because the escape doesn't work
+		if err := L.DoString(code); err != nil {
+			continue
+		}
+
+		if v := L.Get(-1); v != lua.LNil {
+			t.Errorf("%s: os.execute should not be reachable in the sandbox; got: %v", name, v)
+		}
+		L.Pop(1)
+	}
+}
+
+func TestSandboxFS(t *testing.T) {
+	L, base, saveDir := newSandbox(t)
+
+	if err := L.DoString(`assert(fufu.fs.read("story.txt") == "once upon a time")`); err != nil {
+		t.Errorf("files inside the game directory should be readable; got: %v", err)
+	}
+
+	escapes := []string{
+		`fufu.fs.read("../secret.txt")`,
+		`fufu.fs.read("` + filepath.ToSlash(filepath.Join(base, "secret.txt")) + `")`,
+		`fufu.fs.read("link.txt")`,
+		`fufu.fs.list("..")`,
+		`fufu.fs.read_save("../../secret.txt")`,
+		`fufu.fs.write_save("../escaped.txt", "data")`,
+		`fufu.fs.write_save("../../game/story.txt", "data")`,
+	}
+
+	for _, expr := range escapes {
+		if err := L.DoString("return " + expr); err != nil {
+			t.Fatalf("failed to run %q: %v", expr, err)
+		}
+
+		if v := L.Get(-2); v != lua.LNil {
+			t.Errorf("%s should fail; got: %v", expr, v)
+		}
+		L.Pop(2)
+	}
+
+	if _, err := os.Stat(filepath.Join(saveDir, "escaped.txt")); err == nil {
+		t.Errorf("write_save should not write outside of the data directory")
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(base, "game", "story.txt")); string(data) != "once upon a time" {
+		t.Errorf("files of the game directory should not be changed; got: %q", data)
+	}
+
+	if err := L.DoString(`assert(fufu.fs.write_save("flags/route.txt", "true")); assert(fufu.fs.read_save("flags/route.txt") == "true")`); err != nil {
+		t.Errorf("files inside the data directory should be writable and readable; got: %v", err)
+	}
+}
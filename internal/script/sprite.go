@@ -0,0 +1,130 @@
+package script
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// POSITIONS are the named positions of sprites. A position is the horizontal alignment of the sprite in the scene from 0 (left edge) to 1 (right edge)
+var POSITIONS = map[string]float64{
+	"left":   0,
+	"center": 0.5,
+	"right":  1,
+}
+
+// DEFAULT_EXPRESSION is the expression of a sprite that is shown without an expression
+const DEFAULT_EXPRESSION = "default"
+
+// Character is a character table created by the character function
+type Character struct {
+	Name string
+	// Sprites are the image paths of the expressions of the character
+	Sprites map[string]string
+}
+
+// Show is the properties of a sprite shown with the show function
+type Show struct {
+	// At is the horizontal alignment of the sprite. Named positions are changed to their numbers
+	At float64
+	// Z is the order of the sprite. Sprites with a bigger z are drawn over the others and sprites with the same z are drawn in the order they are shown
+	Z int
+}
+
+// ReadCharacter reads the name and sprites of a character table. Sprites that are not valid are skipped and returned as problems
+func ReadCharacter(char *lua.LTable) (*Character, []string) {
+	c := Character{Sprites: map[string]string{}}
+	var problems []string
+
+	if char == nil {
+		return &c, []string{"character must be a table created by character()"}
+	}
+
+	name, ok := char.RawGetString("name").(lua.LString)
+	if !ok {
+		problems = append(problems, "character must have a string name")
+	}
+	c.Name = string(name)
+
+	switch sprites := char.RawGetString("sprites").(type) {
+	case *lua.LTable:
+		sprites.ForEach(func(k, v lua.LValue) {
+			expression, ok := k.(lua.LString)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("expression %s of sprites must be a string", k.String()))
+				return
+			}
+
+			path, ok := v.(lua.LString)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("sprite of expression %q must be a string path; got %s", string(expression), v.Type()))
+				return
+			}
+
+			c.Sprites[string(expression)] = string(path)
+		})
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("sprites must be a table; got %s", sprites.Type()))
+	}
+
+	// Tables don't keep the order of their keys, so problems are sorted to be the same in every run
+	slices.Sort(problems)
+
+	return &c, problems
+}
+
+// ReadShow reads the properties of a show call. Properties that are not set keep their values from base, so a sprite that is shown again stays at its position
+func ReadShow(properties *lua.LTable, base Show) (*Show, []string) {
+	s := base
+	var problems []string
+
+	if properties == nil {
+		return &s, nil
+	}
+
+	switch at := properties.RawGetString("at").(type) {
+	case lua.LString:
+		position, ok := POSITIONS[string(at)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("at must be one of %s or a number; got %q", positionNames(), string(at)))
+			break
+		}
+		s.At = position
+	case lua.LNumber:
+		if at < 0 || at > 1 {
+			problems = append(problems, fmt.Sprintf("at must be a number between 0 and 1; got %v", at))
+			break
+		}
+		s.At = float64(at)
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("at must be a string or a number; got %s", at.Type()))
+	}
+
+	switch z := properties.RawGetString("z").(type) {
+	case lua.LNumber:
+		s.Z = int(z)
+	case *lua.LNilType:
+	default:
+		problems = append(problems, fmt.Sprintf("z must be a number; got %s", z.Type()))
+	}
+
+	return &s, problems
+}
+
+// positionNames returns the named positions in the order of their alignment
+func positionNames() string {
+	names := make([]string, 0, len(POSITIONS))
+	for name := range POSITIONS {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Compare(POSITIONS[a], POSITIONS[b])
+	})
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,66 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReadCharacter(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	if err := L.DoString(`char = {name = "Alice", color = "#ff0000", sprites = {default = "alice.png", happy = "alice_happy.png", [1] = "one.png", sad = 2}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	c, problems := ReadCharacter(L.GetGlobal("char").(*lua.LTable))
+	expectedProblems := []string{
+		"expression 1 of sprites must be a string",
+		"sprite of expression \"sad\" must be a string path; got number",
+	}
+	if !reflect.DeepEqual(problems, expectedProblems) {
+		t.Errorf("problems: expected %q; got: %q", expectedProblems, problems)
+	}
+
+	expected := Character{Name: "Alice", Sprites: map[string]string{"default": "alice.png", "happy": "alice_happy.png"}}
+	if !reflect.DeepEqual(*c, expected) {
+		t.Errorf("character: expected %+v; got: %+v", expected, *c)
+	}
+}
+
+func TestReadShow(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	tests := []struct {
+		properties string
+		expected   Show
+		problems   []string
+	}{
+		{`{at = "left", z = 2}`, Show{At: 0, Z: 2}, nil},
+		{`{at = 0.25}`, Show{At: 0.25, Z: 1}, nil},
+		{`{}`, Show{At: 0.5, Z: 1}, nil},
+		{`{at = "top", z = "front"}`, Show{At: 0.5, Z: 1}, []string{
+			"at must be one of left, center, right or a number; got \"top\"",
+			"z must be a number; got string",
+		}},
+		{`{at = 2}`, Show{At: 0.5, Z: 1}, []string{"at must be a number between 0 and 1; got 2"}},
+	}
+
+	for _, test := range tests {
+		if err := L.DoString("properties = " + test.properties); err != nil {
+			t.Fatal(err)
+		}
+
+		s, problems := ReadShow(L.GetGlobal("properties").(*lua.LTable), Show{At: 0.5, Z: 1})
+		if *s != test.expected {
+			t.Errorf("%s: expected %+v; got: %+v", test.properties, test.expected, *s)
+		}
+
+		if !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("%s: expected problems %q; got: %q", test.properties, test.problems, problems)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package script
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moheb2000/fufu/internal/color"
+	"github.com/moheb2000/fufu/internal/ease"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// TRANSITIONS are the names of the transitions between two scenes
+var TRANSITIONS = []string{
+	"dissolve",
+	"wipe_left", "wipe_right", "wipe_up", "wipe_down",
+	"slide_left", "slide_right", "slide_up", "slide_down",
+	"push_left", "push_right", "push_up", "push_down",
+	"fade",
+	"mask",
+}
+
+// DEFAULT_TRANSITION_DURATION is the duration of transitions without a duration
+const DEFAULT_TRANSITION_DURATION = 500 * time.Millisecond
+
+// Transition is a transition between the old scene and the new one. It's a name like "dissolve" or a table like {name = "fade", duration = 1000, easing = "ease_in_out", color = "#ffffff"}
+type Transition struct {
+	Name     string
+	Duration time.Duration
+	Easing   string
+	// Color is the color that fade goes through
+	Color string
+	// Mask is the path of the grayscale image of mask transitions. Dark pixels of the mask show the new scene first
+	Mask string
+}
+
+// ReadTransition reads the transition property of bg, show or hide. It returns nil if there is no transition or it's not valid
+func ReadTransition(v lua.LValue) (*Transition, []string) {
+	t := Transition{Duration: DEFAULT_TRANSITION_DURATION, Easing: "linear", Color: "#000000"}
+	var problems []string
+
+	switch value := v.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LString:
+		t.Name = string(value)
+	case *lua.LTable:
+		name, ok := value.RawGetString("name").(lua.LString)
+		if !ok {
+			return nil, []string{"transition must have a string name"}
+		}
+		t.Name = string(name)
+
+		switch duration := value.RawGetString("duration").(type) {
+		case lua.LNumber:
+			if duration < 0 {
+				problems = append(problems, fmt.Sprintf("duration of transition must not be negative; got %v", duration))
+				break
+			}
+			t.Duration = time.Duration(float64(duration) * float64(time.Millisecond))
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("duration of transition must be a number; got %s", duration.Type()))
+		}
+
+		switch easing := value.RawGetString("easing").(type) {
+		case lua.LString:
+			if _, ok := ease.Lookup(string(easing)); !ok {
+				problems = append(problems, fmt.Sprintf("easing of transition must be one of %s; got %q", strings.Join(ease.Names(), ", "), string(easing)))
+				break
+			}
+			t.Easing = string(easing)
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("easing of transition must be a string; got %s", easing.Type()))
+		}
+
+		switch c := value.RawGetString("color").(type) {
+		case lua.LString:
+			if _, err := color.ParseHex(string(c)); err != nil {
+				problems = append(problems, fmt.Sprintf("color of transition: %v", err))
+				break
+			}
+			t.Color = string(c)
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("color of transition must be a string; got %s", c.Type()))
+		}
+
+		switch mask := value.RawGetString("mask").(type) {
+		case lua.LString:
+			t.Mask = string(mask)
+		case *lua.LNilType:
+		default:
+			problems = append(problems, fmt.Sprintf("mask of transition must be a string path; got %s", mask.Type()))
+		}
+	default:
+		return nil, []string{fmt.Sprintf("transition must be a string or a table; got %s", v.Type())}
+	}
+
+	if !slices.Contains(TRANSITIONS, t.Name) {
+		return nil, append(problems, fmt.Sprintf("transition must be one of %s; got %q", strings.Join(TRANSITIONS, ", "), t.Name))
+	}
+
+	if t.Name == "mask" && t.Mask == "" {
+		return nil, append(problems, "mask transition must have a mask image")
+	}
+
+	return &t, problems
+}
@@ -0,0 +1,46 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReadTransition(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	tests := []struct {
+		value    string
+		expected *Transition
+		problems []string
+	}{
+		{`nil`, nil, nil},
+		{`"dissolve"`, &Transition{Name: "dissolve", Duration: DEFAULT_TRANSITION_DURATION, Easing: "linear", Color: "#000000"}, nil},
+		{`{name = "fade", duration = 1500, easing = "ease_in_out", color = "#fff"}`, &Transition{Name: "fade", Duration: 1500 * time.Millisecond, Easing: "ease_in_out", Color: "#fff"}, nil},
+		{`{name = "mask", mask = "masks/clock.png", easing = "fast"}`, &Transition{Name: "mask", Duration: DEFAULT_TRANSITION_DURATION, Easing: "linear", Color: "#000000", Mask: "masks/clock.png"}, []string{
+			"easing of transition must be one of ease_in, ease_in_back, ease_in_bounce, ease_in_cubic, ease_in_elastic, ease_in_out, ease_in_out_back, ease_in_out_bounce, ease_in_out_cubic, ease_in_out_elastic, ease_in_out_quad, ease_in_quad, ease_out, ease_out_back, ease_out_bounce, ease_out_cubic, ease_out_elastic, ease_out_quad, linear; got \"fast\"",
+		}},
+		{`{name = "mask"}`, nil, []string{"mask transition must have a mask image"}},
+		{`"spin"`, nil, []string{"transition must be one of dissolve, wipe_left, wipe_right, wipe_up, wipe_down, slide_left, slide_right, slide_up, slide_down, push_left, push_right, push_up, push_down, fade, mask; got \"spin\""}},
+		{`{duration = 100}`, nil, []string{"transition must have a string name"}},
+		{`true`, nil, []string{"transition must be a string or a table; got boolean"}},
+	}
+
+	for _, test := range tests {
+		if err := L.DoString("value = " + test.value); err != nil {
+			t.Fatal(err)
+		}
+
+		transition, problems := ReadTransition(L.GetGlobal("value"))
+		if !reflect.DeepEqual(transition, test.expected) {
+			t.Errorf("%s: expected %+v; got: %+v", test.value, test.expected, transition)
+		}
+
+		if !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("%s: expected problems %q; got: %q", test.value, test.problems, problems)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package script
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moheb2000/fufu/internal/ease"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// TWEEN_TARGETS are the targets of tween and the properties that can be changed on them. Sprites are the targets of character tables and x of a sprite is its position from 0 to 1 like the at property of show. Other x and y values move the target by pixels
+var TWEEN_TARGETS = map[string][]string{
+	"bg":      {"x", "y", "alpha"},
+	"sprite":  {"x", "y", "alpha"},
+	"dialogs": {"x", "y"},
+}
+
+// Tween changes the properties of a target to new values over time. It's a call like tween(alice, {x = 0.8, alpha = 0.5}, 1000, "ease_out")
+type Tween struct {
+	// Target is "bg", "dialogs" or "sprite" for the sprite of Character
+	Target     string
+	Character  string
+	Properties map[string]float64
+	Duration   time.Duration
+	Easing     string
+}
+
+// ReadTween reads the arguments of tween. The target is a character table or the name of a target like "bg"
+func ReadTween(target lua.LValue, properties lua.LValue, duration lua.LValue, easing lua.LValue) (*Tween, []string) {
+	t := Tween{Properties: map[string]float64{}, Easing: "linear"}
+	var problems []string
+
+	switch value := target.(type) {
+	case *lua.LTable:
+		c, cp := ReadCharacter(value)
+		if len(cp) > 0 {
+			return nil, cp
+		}
+		t.Target = "sprite"
+		t.Character = c.Name
+	case lua.LString:
+		if value == "sprite" || TWEEN_TARGETS[string(value)] == nil {
+			return nil, []string{fmt.Sprintf("target of tween must be a character or one of %s; got %q", strings.Join(targetNames(), ", "), string(value))}
+		}
+		t.Target = string(value)
+	default:
+		return nil, []string{fmt.Sprintf("target of tween must be a character or a string; got %s", target.Type())}
+	}
+
+	pt, ok := properties.(*lua.LTable)
+	if !ok {
+		return nil, append(problems, fmt.Sprintf("properties of tween must be a table; got %s", properties.Type()))
+	}
+
+	allowed := TWEEN_TARGETS[t.Target]
+	pt.ForEach(func(k, v lua.LValue) {
+		name, ok := k.(lua.LString)
+		if !ok || !slices.Contains(allowed, string(name)) {
+			problems = append(problems, fmt.Sprintf("property of %s tween must be one of %s; got %s", t.Target, strings.Join(allowed, ", "), k.String()))
+			return
+		}
+
+		n, ok := v.(lua.LNumber)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s of tween must be a number; got %s", name, v.Type()))
+			return
+		}
+
+		if name == "alpha" && (n < 0 || n > 1) {
+			problems = append(problems, fmt.Sprintf("alpha of tween must be a number from 0 to 1; got %v", n))
+			return
+		}
+
+		t.Properties[string(name)] = float64(n)
+	})
+	// Problems of properties are found in the order of the table, so they are sorted to be the same in every run
+	slices.Sort(problems)
+
+	d, dp := readDuration("duration of tween", duration)
+	problems = append(problems, dp...)
+	t.Duration = d
+
+	e, ep := readEasing("easing of tween", easing)
+	problems = append(problems, ep...)
+	t.Easing = e
+
+	return &t, problems
+}
+
+// ReadWait reads the duration of wait in milliseconds
+func ReadWait(duration lua.LValue) (time.Duration, []string) {
+	return readDuration("duration of wait", duration)
+}
+
+// readDuration reads a duration in milliseconds that can't be negative
+func readDuration(name string, v lua.LValue) (time.Duration, []string) {
+	n, ok := v.(lua.LNumber)
+	if !ok {
+		return 0, []string{fmt.Sprintf("%s must be a number of milliseconds; got %s", name, v.Type())}
+	}
+
+	if n < 0 {
+		return 0, []string{fmt.Sprintf("%s must not be negative; got %v", name, n)}
+	}
+
+	return time.Duration(float64(n) * float64(time.Millisecond)), nil
+}
+
+// readEasing reads the name of an easing curve. It's "linear" if v is nil or not valid
+func readEasing(name string, v lua.LValue) (string, []string) {
+	switch value := v.(type) {
+	case lua.LString:
+		if _, ok := ease.Lookup(string(value)); !ok {
+			return "linear", []string{fmt.Sprintf("%s must be one of %s; got %q", name, strings.Join(ease.Names(), ", "), string(value))}
+		}
+
+		return string(value), nil
+	case *lua.LNilType:
+		return "linear", nil
+	}
+
+	return "linear", []string{fmt.Sprintf("%s must be a string; got %s", name, v.Type())}
+}
+
+// targetNames returns the names of the tween targets that scripts can use as strings
+func targetNames() []string {
+	names := make([]string, 0, len(TWEEN_TARGETS))
+	for name := range TWEEN_TARGETS {
+		if name != "sprite" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,62 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReadTween(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+
+	tests := []struct {
+		args     string
+		expected *Tween
+		problems []string
+	}{
+		{`{name = "Alice"}, {x = 0.8, alpha = 0.5}, 1000, "ease_out"`, &Tween{Target: "sprite", Character: "Alice", Properties: map[string]float64{"x": 0.8, "alpha": 0.5}, Duration: time.Second, Easing: "ease_out"}, nil},
+		{`"bg", {y = -20}, 250`, &Tween{Target: "bg", Properties: map[string]float64{"y": -20}, Duration: 250 * time.Millisecond, Easing: "linear"}, nil},
+		{`"dialogs", {alpha = 0, x = "left"}, -1, 2`, &Tween{Target: "dialogs", Properties: map[string]float64{}, Easing: "linear"}, []string{
+			"property of dialogs tween must be one of x, y; got alpha",
+			"x of tween must be a number; got string",
+			"duration of tween must not be negative; got -1",
+			"easing of tween must be a string; got number",
+		}},
+		{`"bg", {alpha = 2}`, &Tween{Target: "bg", Properties: map[string]float64{}, Easing: "linear"}, []string{
+			"alpha of tween must be a number from 0 to 1; got 2",
+			"duration of tween must be a number of milliseconds; got nil",
+		}},
+		{`"camera", {x = 1}, 100`, nil, []string{"target of tween must be a character or one of bg, dialogs; got \"camera\""}},
+		{`{}, {x = 1}, 100`, nil, []string{"character must have a string name"}},
+		{`"bg", 1, 100`, nil, []string{"properties of tween must be a table; got number"}},
+	}
+
+	for _, test := range tests {
+		if err := L.DoString("args = {" + test.args + "}"); err != nil {
+			t.Fatal(err)
+		}
+
+		args := L.GetGlobal("args").(*lua.LTable)
+		tw, problems := ReadTween(args.RawGetInt(1), args.RawGetInt(2), args.RawGetInt(3), args.RawGetInt(4))
+		if !reflect.DeepEqual(tw, test.expected) {
+			t.Errorf("%s: expected %+v; got: %+v", test.args, test.expected, tw)
+		}
+
+		if !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("%s: expected problems %q; got: %q", test.args, test.problems, problems)
+		}
+	}
+}
+
+func TestReadWait(t *testing.T) {
+	if d, problems := ReadWait(lua.LNumber(1500)); d != 1500*time.Millisecond || problems != nil {
+		t.Errorf("1500: expected 1.5s without problems; got: %v and %q", d, problems)
+	}
+
+	if _, problems := ReadWait(lua.LString("1s")); len(problems) != 1 {
+		t.Errorf("\"1s\": expected a problem; got: %q", problems)
+	}
+}
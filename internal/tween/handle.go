@@ -0,0 +1,81 @@
+package tween
+
+import "time"
+
+// Handle runs a tween in the animation manager. The tween can be cancelled or fast-forwarded to its end with its handle
+type Handle struct {
+	Name      string
+	tween     Tween
+	done      bool
+	cancelled bool
+	callbacks []func()
+}
+
+// NewHandle returns a handle of the tween. Name is used by the animation manager to find the tween and it can be empty
+func NewHandle(name string, t Tween) *Handle {
+	return &Handle{Name: name, tween: t}
+}
+
+// Animation returns a func(time.Duration) bool that can be added to animation manager to run the tween
+func (h *Handle) Animation() func(time.Duration) bool {
+	return func(dt time.Duration) bool {
+		if h.done {
+			return true
+		}
+
+		if _, done := h.tween.Update(dt); done {
+			h.complete()
+		}
+
+		return h.done
+	}
+}
+
+// OnComplete adds a function that is called when the tween is finished or fast-forwarded. Cancelled tweens don't call it
+func (h *Handle) OnComplete(fn func()) *Handle {
+	h.callbacks = append(h.callbacks, fn)
+
+	return h
+}
+
+// Cancel stops the tween where it is
+func (h *Handle) Cancel() {
+	if h.done {
+		return
+	}
+
+	h.done = true
+	h.cancelled = true
+}
+
+// FastForward jumps to the end of the tween
+func (h *Handle) FastForward() {
+	if h.done {
+		return
+	}
+
+	h.tween.Finish()
+	h.complete()
+}
+
+// Done checks if the tween is finished or cancelled
+func (h *Handle) Done() bool {
+	return h.done
+}
+
+// Cancelled checks if the tween is stopped with Cancel
+func (h *Handle) Cancelled() bool {
+	return h.cancelled
+}
+
+// complete finishes the handle and calls its completion functions
+func (h *Handle) complete() {
+	if h.done {
+		return
+	}
+	h.done = true
+
+	for _, fn := range h.callbacks {
+		fn()
+	}
+}
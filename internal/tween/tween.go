@@ -0,0 +1,245 @@
+// tween package animates values over time with easing curves. Tweens can be put in sequences and parallel groups, delayed and looped, and a Handle runs them in the animation manager
+package tween
+
+import (
+	"time"
+
+	"github.com/moheb2000/fufu/internal/ease"
+)
+
+// Tween is an animation that changes something over time
+type Tween interface {
+	// Update runs the tween for dt. It returns the part of dt that is left after the tween is finished, so the next tween of a sequence can use it, and true if the tween is finished
+	Update(dt time.Duration) (time.Duration, bool)
+	// Finish jumps to the end of the tween
+	Finish()
+	// Reset makes the tween ready to run again from its start
+	Reset()
+}
+
+// value is a tween that calls its step function with the eased progress
+type value struct {
+	duration time.Duration
+	easing   ease.Func
+	// start is called before the first step, so the tween can read the values that it starts from
+	start   func()
+	step    func(p float64)
+	elapsed time.Duration
+	started bool
+	done    bool
+}
+
+// New returns a tween that calls step with the eased progress from 0 to 1 in every update. If easing is nil, the progress is linear
+func New(duration time.Duration, easing ease.Func, step func(p float64)) Tween {
+	if easing == nil {
+		easing = ease.Linear
+	}
+
+	return &value{duration: duration, easing: easing, step: step}
+}
+
+// To returns a tween that changes v from its value at the start of the tween to the target
+func To(v *float64, target float64, duration time.Duration, easing ease.Func) Tween {
+	var from float64
+	t := New(duration, easing, func(p float64) {
+		*v = from + (target-from)*p
+	}).(*value)
+	t.start = func() {
+		from = *v
+	}
+
+	return t
+}
+
+// Delay returns a tween that only waits for the duration
+func Delay(duration time.Duration) Tween {
+	return New(duration, nil, func(float64) {})
+}
+
+// Call returns a tween that calls fn and finishes at once. It can be used in a sequence to run code between tweens
+func Call(fn func()) Tween {
+	return New(0, nil, func(p float64) {
+		if p == 1 {
+			fn()
+		}
+	})
+}
+
+func (v *value) Update(dt time.Duration) (time.Duration, bool) {
+	if v.done {
+		return dt, true
+	}
+
+	v.begin()
+
+	v.elapsed += dt
+	if v.elapsed >= v.duration {
+		rest := v.elapsed - v.duration
+		v.Finish()
+
+		return rest, true
+	}
+
+	v.step(v.easing(ease.Progress(v.elapsed, v.duration)))
+
+	return 0, false
+}
+
+func (v *value) Finish() {
+	if v.done {
+		return
+	}
+
+	v.begin()
+	v.elapsed = v.duration
+	v.done = true
+	v.step(1)
+}
+
+func (v *value) Reset() {
+	v.elapsed = 0
+	v.started = false
+	v.done = false
+}
+
+// begin calls the start function once in every run
+func (v *value) begin() {
+	if v.started {
+		return
+	}
+
+	v.started = true
+	if v.start != nil {
+		v.start()
+	}
+}
+
+// sequence runs its tweens one after another
+type sequence struct {
+	tweens  []Tween
+	current int
+}
+
+// Sequence returns a tween that runs the tweens one after another
+func Sequence(tweens ...Tween) Tween {
+	return &sequence{tweens: tweens}
+}
+
+func (s *sequence) Update(dt time.Duration) (time.Duration, bool) {
+	for s.current < len(s.tweens) {
+		rest, done := s.tweens[s.current].Update(dt)
+		if !done {
+			return 0, false
+		}
+
+		s.current++
+		dt = rest
+	}
+
+	return dt, true
+}
+
+func (s *sequence) Finish() {
+	for ; s.current < len(s.tweens); s.current++ {
+		s.tweens[s.current].Finish()
+	}
+}
+
+func (s *sequence) Reset() {
+	s.current = 0
+	for _, t := range s.tweens {
+		t.Reset()
+	}
+}
+
+// parallel runs its tweens together
+type parallel struct {
+	tweens []Tween
+}
+
+// Parallel returns a tween that runs the tweens together. It's finished when all of them are finished
+func Parallel(tweens ...Tween) Tween {
+	return &parallel{tweens: tweens}
+}
+
+func (p *parallel) Update(dt time.Duration) (time.Duration, bool) {
+	rest := dt
+	finished := true
+	for _, t := range p.tweens {
+		r, done := t.Update(dt)
+		if !done {
+			finished = false
+		}
+		rest = min(rest, r)
+	}
+
+	if !finished {
+		return 0, false
+	}
+
+	return rest, true
+}
+
+func (p *parallel) Finish() {
+	for _, t := range p.tweens {
+		t.Finish()
+	}
+}
+
+func (p *parallel) Reset() {
+	for _, t := range p.tweens {
+		t.Reset()
+	}
+}
+
+// loop runs its tween again after it's finished
+type loop struct {
+	tween Tween
+	count int
+	runs  int
+	done  bool
+}
+
+// Loop returns a tween that runs the tween count times. If count is 0, it runs forever until it's finished with Finish
+func Loop(t Tween, count int) Tween {
+	return &loop{tween: t, count: count}
+}
+
+func (l *loop) Update(dt time.Duration) (time.Duration, bool) {
+	if l.done {
+		return dt, true
+	}
+
+	for {
+		rest, done := l.tween.Update(dt)
+		if !done {
+			return 0, false
+		}
+
+		l.runs++
+		if l.count > 0 && l.runs >= l.count {
+			l.done = true
+			return rest, true
+		}
+
+		l.tween.Reset()
+
+		// A tween without a duration runs once in every update, otherwise a loop without a count never returns
+		if rest == 0 || rest == dt {
+			return 0, false
+		}
+		dt = rest
+	}
+}
+
+// Finish finishes the current run of the tween and stops the loop
+func (l *loop) Finish() {
+	l.tween.Finish()
+	l.done = true
+}
+
+func (l *loop) Reset() {
+	l.tween.Reset()
+	l.runs = 0
+	l.done = false
+}
@@ -0,0 +1,113 @@
+package tween
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moheb2000/fufu/internal/ease"
+)
+
+func TestTo(t *testing.T) {
+	x := 10.0
+	tw := To(&x, 20, time.Second, ease.InQuad)
+
+	// The tween starts from the value at its first update
+	x = 0
+	if _, done := tw.Update(500 * time.Millisecond); done || x != 5 {
+		t.Errorf("half of the tween: expected 5 and not finished; got: %v and %v", x, done)
+	}
+
+	rest, done := tw.Update(700 * time.Millisecond)
+	if !done || x != 20 || rest != 200*time.Millisecond {
+		t.Errorf("end of the tween: expected 20 with 200ms left; got: %v with %v left and finished %v", x, rest, done)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	x, y := 0.0, 0.0
+	var calls []string
+	tw := Sequence(
+		To(&x, 1, time.Second, nil),
+		Call(func() { calls = append(calls, "middle") }),
+		Delay(500*time.Millisecond),
+		To(&y, 2, time.Second, nil),
+	)
+
+	tw.Update(1250 * time.Millisecond)
+	if x != 1 || y != 0 || len(calls) != 1 {
+		t.Errorf("after the first tween: expected x 1, y 0 and one call; got: %v, %v and %v", x, y, calls)
+	}
+
+	tw.Update(750 * time.Millisecond)
+	if y != 1 {
+		t.Errorf("after the delay: expected y 1; got: %v", y)
+	}
+
+	tw.Finish()
+	if y != 2 {
+		t.Errorf("finish: expected y 2; got: %v", y)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	x, y := 0.0, 0.0
+	tw := Parallel(To(&x, 1, time.Second, nil), To(&y, 1, 2*time.Second, nil))
+
+	if _, done := tw.Update(time.Second); done || x != 1 || y != 0.5 {
+		t.Errorf("after one second: expected 1 and 0.5 and not finished; got: %v and %v and %v", x, y, done)
+	}
+
+	rest, done := tw.Update(1500 * time.Millisecond)
+	if !done || rest != 500*time.Millisecond {
+		t.Errorf("end: expected to finish with 500ms left; got: %v with %v left", done, rest)
+	}
+}
+
+func TestLoop(t *testing.T) {
+	runs := 0
+	tw := Loop(Sequence(Delay(time.Second), Call(func() { runs++ })), 3)
+
+	if _, done := tw.Update(2500 * time.Millisecond); done || runs != 2 {
+		t.Errorf("after 2.5 seconds: expected 2 runs and not finished; got: %v and %v", runs, done)
+	}
+
+	if rest, done := tw.Update(time.Second); !done || runs != 3 || rest != 500*time.Millisecond {
+		t.Errorf("end: expected 3 runs with 500ms left; got: %v runs with %v left and finished %v", runs, rest, done)
+	}
+
+	forever := Loop(Call(func() { runs++ }), 0)
+	if _, done := forever.Update(time.Second); done {
+		t.Errorf("loop without a count: expected not to finish")
+	}
+
+	forever.Finish()
+	if _, done := forever.Update(time.Second); !done {
+		t.Errorf("finished loop: expected to be finished")
+	}
+}
+
+func TestHandle(t *testing.T) {
+	x := 0.0
+	completed := 0
+	h := NewHandle("move", To(&x, 1, time.Second, nil)).OnComplete(func() { completed++ })
+	animation := h.Animation()
+
+	if animation(500*time.Millisecond) || x != 0.5 {
+		t.Errorf("half of the tween: expected 0.5 and running; got: %v and %v", x, h.Done())
+	}
+
+	h.FastForward()
+	if !animation(0) || x != 1 || completed != 1 {
+		t.Errorf("fast-forward: expected 1 and one completion; got: %v and %v", x, completed)
+	}
+
+	x = 0
+	h = NewHandle("", To(&x, 1, time.Second, nil)).OnComplete(func() { completed++ })
+	animation = h.Animation()
+	animation(250 * time.Millisecond)
+	h.Cancel()
+
+	if !animation(time.Second) || x != 0.25 || completed != 1 || !h.Cancelled() {
+		t.Errorf("cancel: expected to stop at 0.25 without completion; got: %v and %v completions", x, completed)
+	}
+}
@@ -0,0 +1,126 @@
+// watch package checks a directory for changed files. It's used by dev mode to reload the game when its files change
+package watch
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Watcher checks the files of a directory in an interval and sends the changed files to the Changes channel. It doesn't use the file system notifications of the operating system, so it works the same way everywhere
+type Watcher struct {
+	root     string
+	interval time.Duration
+	files    map[string]time.Time
+	// Changes receives the paths of added, changed and removed files relative to root
+	Changes chan []string
+	done    chan struct{}
+}
+
+// New returns a Watcher for root. The current files of root are not reported as changes
+func New(root string, interval time.Duration) (*Watcher, error) {
+	w := Watcher{
+		root:     root,
+		interval: interval,
+		files:    make(map[string]time.Time),
+		Changes:  make(chan []string, 1),
+		done:     make(chan struct{}),
+	}
+
+	if _, err := w.scan(); err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// Start checks the files in a new goroutine until Close is called
+func (w *Watcher) Start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				changes, err := w.scan()
+				if err != nil || len(changes) == 0 {
+					continue
+				}
+
+				select {
+				case w.Changes <- changes:
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Close stops checking the files
+func (w *Watcher) Close() {
+	close(w.done)
+}
+
+// scan walks the root directory and returns the files that are changed since the last scan
+func (w *Watcher) scan() ([]string, error) {
+	files := make(map[string]time.Time)
+
+	err := filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip hidden files and directories like .git
+		if path != w.root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(w.root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = info.ModTime()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []string{}
+	for path, modTime := range files {
+		if old, exists := w.files[path]; !exists || !old.Equal(modTime) {
+			changes = append(changes, path)
+		}
+	}
+
+	for path := range w.files {
+		if _, exists := files[path]; !exists {
+			changes = append(changes, path)
+		}
+	}
+
+	w.files = files
+	slices.Sort(changes)
+
+	return changes, nil
+}
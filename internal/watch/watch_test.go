@@ -0,0 +1,60 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.lua", "old.lua", ".hidden"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("-- file"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := New(root, time.Second)
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+
+	changes, _ := w.scan()
+	if len(changes) != 0 {
+		t.Errorf("files should not change without editing them; got: %v", changes)
+	}
+
+	// Change the modification time instead of waiting, because some file systems don't have precise times
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(root, "main.lua"), later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(filepath.Join(root, "old.lua")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "assets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "assets", "bg.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".hidden"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = w.scan()
+	if err != nil {
+		t.Fatalf("scan returned an error: %v", err)
+	}
+
+	expected := []string{"assets/bg.png", "main.lua", "old.lua"}
+	if !slices.Equal(changes, expected) {
+		t.Errorf("changes expected %v; got: %v", expected, changes)
+	}
+}